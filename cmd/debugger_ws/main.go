@@ -2,28 +2,46 @@ package main
 
 import (
 	"bytes"
+	"context"
 	"encoding/base64"
 	"flag"
 	"fmt"
 	"github.com/gorilla/websocket"
+	"github.com/mr-tim/goboye/internal/pkg/cheats"
 	"github.com/mr-tim/goboye/internal/pkg/cpu"
+	"github.com/mr-tim/goboye/internal/pkg/debugger/trace"
 	"github.com/mr-tim/goboye/internal/pkg/goboye"
 	"github.com/pkg/profile"
 	"image/png"
 	"log"
 	"net/http"
 	_ "net/http/pprof"
+	"os"
+	"os/signal"
 	"strings"
 	"sync"
+	"syscall"
 )
 
 var (
-	addr       = flag.String("addr", "127.0.0.1:8080", "http service address")
-	rom        = flag.String("rom", "", "ROM to run")
-	profileCpu = flag.Bool("profileCpu", false, "Profile CPU")
-	profileMem = flag.Bool("profileMem", false, "Profile memory")
+	addr          = flag.String("addr", "127.0.0.1:8080", "http service address")
+	rom           = flag.String("rom", "", "ROM to run (.gb/.gbc, or a .zip/.gz archive - use rom.zip#name.gb to pick a file from a zip)")
+	patchFile     = flag.String("patch", "", "IPS, UPS or BPS patch to apply to the ROM (defaults to one named after the ROM, eg. game.ips)")
+	bootRom       = flag.String("bootrom", "", "Boot ROM to run instead of the built in DMG one (256 byte DMG/MGB/SGB, or 2304 byte CGB)")
+	model         = flag.String("model", "", "Hardware to emulate: dmg, mgb, sgb or cgb (defaults to dmg, or cgb for a CGB boot ROM)")
+	skipBoot      = flag.Bool("skipboot", false, "Skip the boot ROM, starting the game with the registers it would leave")
+	cycleAccurate = flag.Bool("cycleaccurate", false, "Run the timers and display on every memory access rather than once per instruction (slower, but more accurate)")
+	traceFile     = flag.String("trace", "", "Write a Gameboy Doctor style trace of every instruction to this file")
+	doctor        = flag.Bool("doctor", false, "Make LY always read 0x90, as Gameboy Doctor's reference logs expect")
+	profileCpu    = flag.Bool("profileCpu", false, "Profile CPU")
+	profileMem    = flag.Bool("profileMem", false, "Profile memory")
 )
 
+// tracer is shared by every connection, so reconnecting carries on the same
+// trace rather than starting it again. Each connection flushes it as it
+// closes.
+var tracer *trace.Writer
+
 var upgrader = websocket.Upgrader{
 	CheckOrigin: func(r *http.Request) bool {
 		return strings.HasPrefix(r.RemoteAddr, "127.0.0.1:") || strings.HasPrefix(r.RemoteAddr, "localhost:")
@@ -45,11 +63,19 @@ func serveWs(w http.ResponseWriter, r *http.Request) {
 		emulator:  goboye.NewEmulator(),
 		closeOnce: &(sync.Once{}),
 	}
+	client.emulator.SetCycleAccurate(*cycleAccurate)
+	client.emulator.SetDoctor(*doctor)
+	client.emulator.SetTraceWriter(tracer)
+	if err := client.emulator.ConfigureBoot(*bootRom, *model, *skipBoot); err != nil {
+		log.Printf("Unable to configure boot: %s", err)
+		client.close()
+		return
+	}
 	go client.writeMessages()
 	go client.readMessages()
 	go client.handleMessages()
 
-	client.emulator.LoadRomImage(*rom)
+	client.emulator.LoadPatchedRomImage(*rom, *patchFile)
 	client.refreshState()
 }
 
@@ -72,6 +98,35 @@ type UpdateMessage struct {
 	Breakpoints   []uint16       `json:"breakpoints"`
 	DebugImage    string         `json:"debug_image"`
 	Flags         Flags          `json:"flags"`
+	RomBank       int            `json:"rom_bank"`
+	Clock         uint64         `json:"clock"`
+	Cheats        []Cheat        `json:"cheats"`
+	Search        *SearchState   `json:"search"`
+	Lockup        *Lockup        `json:"lockup"`
+}
+
+// Lockup is set once the cpu has run an illegal opcode and hung.
+type Lockup struct {
+	Address uint16 `json:"address"`
+	Opcode  byte   `json:"opcode"`
+}
+
+type SearchState struct {
+	Width   int            `json:"width"`
+	Count   int            `json:"count"`
+	Results []SearchResult `json:"results"`
+}
+
+type SearchResult struct {
+	Address  uint16 `json:"address"`
+	Value    uint16 `json:"value"`
+	Previous uint16 `json:"previous"`
+}
+
+type Cheat struct {
+	Code    string `json:"code"`
+	Name    string `json:"name"`
+	Enabled bool   `json:"enabled"`
 }
 
 type MemoryUpdate struct {
@@ -95,6 +150,8 @@ type CommandMessage struct {
 	Step       *StepCommand       `json:"step"`
 	Breakpoint *BreakpointCommand `json:"breakpoint"`
 	Continue   *ContinueCommand   `json:"continue"`
+	Cheat      *CheatCommand      `json:"cheat"`
+	Search     *SearchCommand     `json:"search"`
 }
 
 type StepCommand struct {
@@ -108,11 +165,34 @@ type BreakpointCommand struct {
 type ContinueCommand struct {
 }
 
+// CheatCommand adds a cheat, or updates it if it's already been added.
+type CheatCommand struct {
+	Code    string `json:"code"`
+	Name    string `json:"name"`
+	Enabled bool   `json:"enabled"`
+	Remove  bool   `json:"remove"`
+}
+
 type Instruction struct {
 	Address     int    `json:"address"`
 	Disassembly string `json:"disassembly"`
+	Cycles      string `json:"cycles"`
+}
+
+// SearchCommand drives the ram search. Action is "start" to begin a search of
+// Width (8 or 16) bit values, "filter" to keep the values passing Comparison
+// (equal to Value, changed, unchanged, increased or decreased since the last
+// filter), or "clear" to end it.
+type SearchCommand struct {
+	Action     string `json:"action"`
+	Width      int    `json:"width"`
+	Comparison string `json:"comparison"`
+	Value      uint16 `json:"value"`
 }
 
+// only the first few search results are sent with each update
+const searchResultLimit = 100
+
 func (c *Client) readMessages() {
 	for {
 		var msg InboundMessage
@@ -178,16 +258,69 @@ func (c *Client) handleMessages() {
 				log.Print("Received continue command")
 				c.emulator.ContinueDebugging(false)
 				c.refreshState()
+			} else if cmd.Cheat != nil {
+				log.Print("Received cheat command")
+				if err := c.updateCheat(cmd.Cheat); err != nil {
+					log.Printf("Unable to update cheat %s: %s", cmd.Cheat.Code, err)
+				}
+				c.refreshState()
+			} else if cmd.Search != nil {
+				log.Print("Received search command")
+				if err := c.updateSearch(cmd.Search); err != nil {
+					log.Printf("Unable to %s search: %s", cmd.Search.Action, err)
+				}
+				c.refreshState()
 			}
 		}
 	}
 }
 
+func (c *Client) updateSearch(cmd *SearchCommand) error {
+	switch cmd.Action {
+	case "start":
+		width := cheats.Width8
+		if cmd.Width == 16 {
+			width = cheats.Width16
+		} else if cmd.Width != 8 {
+			return fmt.Errorf("width must be 8 or 16, not %d", cmd.Width)
+		}
+		c.emulator.StartRamSearch(width)
+	case "filter":
+		comparison, err := cheats.ParseComparison(cmd.Comparison)
+		if err != nil {
+			return err
+		}
+		_, err = c.emulator.FilterRamSearch(comparison, cmd.Value)
+		return err
+	case "clear":
+		c.emulator.ClearRamSearch()
+	default:
+		return fmt.Errorf("unknown search action %q", cmd.Action)
+	}
+	return nil
+}
+
+func (c *Client) updateCheat(cmd *CheatCommand) error {
+	if cmd.Remove {
+		return c.emulator.RemoveCheat(cmd.Code)
+	}
+	if err := c.emulator.SetCheatEnabled(cmd.Code, cmd.Enabled); err == nil {
+		return nil
+	}
+	if _, err := c.emulator.AddCheat(cmd.Code, cmd.Name); err != nil {
+		return err
+	}
+	return c.emulator.SetCheatEnabled(cmd.Code, cmd.Enabled)
+}
+
 func (c *Client) close() {
 	c.closeOnce.Do(func() {
 		close(c.outbox)
 		close(c.inbox)
 		c.conn.Close()
+		if err := c.emulator.Close(); err != nil {
+			log.Printf("Unable to write save file: %s", err)
+		}
 		fmt.Printf("Closed outbox and inbox\n")
 	})
 }
@@ -204,6 +337,7 @@ func (c *Client) refreshState() {
 		instructions = append(instructions, Instruction{
 			Address:     int(addr),
 			Disassembly: o.Disassembly(),
+			Cycles:      o.FormatCycles(),
 		})
 	}
 
@@ -215,6 +349,28 @@ func (c *Client) refreshState() {
 	}
 	base64debugImage := base64.StdEncoding.EncodeToString(b.Bytes())
 
+	cheatList := make([]Cheat, 0)
+	for _, ch := range c.emulator.Cheats() {
+		cheatList = append(cheatList, Cheat{Code: ch.Code, Name: ch.Name, Enabled: ch.Enabled})
+	}
+
+	var search *SearchState
+	if s := c.emulator.RamSearch(); s != nil {
+		search = &SearchState{
+			Width:   int(s.Width()) * 8,
+			Count:   s.Count(),
+			Results: make([]SearchResult, 0),
+		}
+		for _, r := range c.emulator.RamSearchResults(searchResultLimit) {
+			search.Results = append(search.Results, SearchResult{r.Address, r.Value, r.Previous})
+		}
+	}
+
+	var lockup *Lockup
+	if l, locked := c.emulator.Lockup(); locked {
+		lockup = &Lockup{Address: l.Address, Opcode: l.Opcode}
+	}
+
 	msg := OutboundMessage{
 		Update: UpdateMessage{
 			Instructions: instructions,
@@ -241,6 +397,11 @@ func (c *Client) refreshState() {
 				H: c.emulator.GetFlagValue(cpu.FlagH),
 				C: c.emulator.GetFlagValue(cpu.FlagC),
 			},
+			RomBank: c.emulator.GetRomBank(),
+			Clock:   c.emulator.Clock(),
+			Cheats:  cheatList,
+			Search:  search,
+			Lockup:  lockup,
 		},
 	}
 
@@ -260,7 +421,33 @@ func main() {
 		defer profile.Start(profile.MemProfile, profile.ProfilePath(".")).Stop()
 	}
 
+	if *traceFile != "" {
+		f, err := os.Create(*traceFile)
+		if err != nil {
+			panic(err)
+		}
+		defer f.Close()
+		tracer = trace.NewWriter(f)
+		defer func() {
+			if err := tracer.Flush(); err != nil {
+				log.Printf("Unable to write trace: %s", err)
+			}
+		}()
+	}
+
+	// shut down cleanly on ctrl-c, so the deferred flushes run
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	server := &http.Server{Addr: *addr}
+	go func() {
+		<-ctx.Done()
+		log.Printf("Shutting down...")
+		server.Shutdown(context.Background())
+	}()
+
 	http.HandleFunc("/ws", serveWs)
 	log.Printf("Listening on %s...", *addr)
-	log.Fatal(http.ListenAndServe(*addr, nil))
+	if err := server.ListenAndServe(); err != http.ErrServerClosed {
+		log.Printf("Server stopped: %s", err)
+	}
 }
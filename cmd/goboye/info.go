@@ -0,0 +1,52 @@
+package main
+
+import (
+	"fmt"
+	"github.com/mr-tim/goboye/internal/pkg/cartridge"
+	"github.com/mr-tim/goboye/internal/pkg/romfile"
+	"io"
+	"log"
+	"os"
+)
+
+// runInfo implements `goboye info rom.gb`, printing the cartridge header.
+func runInfo(args []string) {
+	if len(args) != 1 {
+		fmt.Fprintf(os.Stderr, "Usage: goboye info /path/to/rom.gb\n")
+		os.Exit(2)
+	}
+
+	rom, err := romfile.Open(args[0])
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	h, err := cartridge.Parse(rom)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	printInfo(os.Stdout, h)
+}
+
+func printInfo(w io.Writer, h cartridge.Header) {
+	fmt.Fprintf(w, "Title:            %s\n", h.Title)
+	fmt.Fprintf(w, "Manufacturer:     %s\n", h.ManufacturerCode)
+	fmt.Fprintf(w, "CGB flag:         %02X (%s)\n", h.CgbFlag, h.CgbSupport())
+	fmt.Fprintf(w, "SGB flag:         %02X (supported: %t)\n", h.SgbFlag, h.SupportsSgb())
+	fmt.Fprintf(w, "Cartridge type:   %02X (%s)\n", byte(h.Type), h.Type)
+	fmt.Fprintf(w, "ROM size:         %02X (%d KiB)\n", h.RomSizeCode, h.RomSize()/1024)
+	fmt.Fprintf(w, "RAM size:         %02X (%d KiB)\n", h.RamSizeCode, h.RamSize()/1024)
+	fmt.Fprintf(w, "Destination:      %02X (japanese: %t)\n", h.DestinationCode, h.IsJapanese())
+	fmt.Fprintf(w, "Licensee:         %s (%s)\n", h.LicenseeCode(), h.Licensee())
+	fmt.Fprintf(w, "Version:          %02X\n", h.Version)
+	fmt.Fprintf(w, "Header checksum:  %02X (%s)\n", h.HeaderChecksum, checksumStatus(h.IsHeaderChecksumValid()))
+	fmt.Fprintf(w, "Global checksum:  %04X (%s)\n", h.GlobalChecksum, checksumStatus(h.IsGlobalChecksumValid()))
+}
+
+func checksumStatus(valid bool) string {
+	if valid {
+		return "ok"
+	}
+	return "bad"
+}
@@ -9,16 +9,30 @@ import (
 	"github.com/pkg/profile"
 	"github.com/veandco/go-sdl2/sdl"
 	"log"
+	"os"
 	"time"
 )
 
 var (
-	rom        = flag.String("rom", "", "ROM to run")
-	profileCpu = flag.Bool("profileCpu", false, "Profile CPU")
-	profileMem = flag.Bool("profileMem", false, "Profile memory")
+	rom           = flag.String("rom", "", "ROM to run (.gb/.gbc, or a .zip/.gz archive - use rom.zip#name.gb to pick a file from a zip)")
+	patchFile     = flag.String("patch", "", "IPS, UPS or BPS patch to apply to the ROM (defaults to one named after the ROM, eg. game.ips)")
+	bootRom       = flag.String("bootrom", "", "Boot ROM to run instead of the built in DMG one (256 byte DMG/MGB/SGB, or 2304 byte CGB)")
+	model         = flag.String("model", "", "Hardware to emulate: dmg, mgb, sgb or cgb (defaults to dmg, or cgb for a CGB boot ROM)")
+	skipBoot      = flag.Bool("skipboot", false, "Skip the boot ROM, starting the game with the registers it would leave")
+	cycleAccurate = flag.Bool("cycleaccurate", false, "Run the timers and display on every memory access rather than once per instruction (slower, but more accurate)")
+	decodeCache   = flag.Bool("decodecache", false, "Cache instructions decoded from the ROM")
+	traceFile     = flag.String("trace", "", "Write a Gameboy Doctor style trace of every instruction to this file")
+	doctor        = flag.Bool("doctor", false, "Make LY always read 0x90, as Gameboy Doctor's reference logs expect")
+	profileCpu    = flag.Bool("profileCpu", false, "Profile CPU")
+	profileMem    = flag.Bool("profileMem", false, "Profile memory")
 )
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "info" {
+		runInfo(os.Args[2:])
+		return
+	}
+
 	flag.Parse()
 
 	if *rom == "" {
@@ -32,7 +46,26 @@ func main() {
 	}
 
 	emulator := goboye.NewEmulator()
-	emulator.LoadRomImage(*rom)
+	emulator.SetCycleAccurate(*cycleAccurate)
+	emulator.SetDecodeCache(*decodeCache)
+	emulator.SetDoctor(*doctor)
+	if *traceFile != "" {
+		f, err := os.Create(*traceFile)
+		if err != nil {
+			panic(err)
+		}
+		defer f.Close()
+		emulator.SetTrace(f)
+	}
+	if err := emulator.ConfigureBoot(*bootRom, *model, *skipBoot); err != nil {
+		panic(err)
+	}
+	emulator.LoadPatchedRomImage(*rom, *patchFile)
+	defer func() {
+		if err := emulator.Close(); err != nil {
+			log.Printf("Unable to write save file: %s", err)
+		}
+	}()
 
 	if err := sdl.Init(sdl.INIT_EVERYTHING); err != nil {
 		panic(err)
@@ -89,7 +122,7 @@ func main() {
 		eventStart = time.Now()
 
 		//redraw
-		i := emulator.DebugRender()
+		i := emulator.Frame()
 		renderTime := time.Since(eventStart)
 		eventStart = time.Now()
 
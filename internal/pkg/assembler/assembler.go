@@ -0,0 +1,481 @@
+// Package assembler turns SM83 assembly, written in RGBDS syntax, into
+// machine code. Instructions are looked up in the cpu package's opcode
+// tables, so it understands exactly what the emulator runs.
+//
+// Besides instructions it supports labels (with .local labels scoped to the
+// label before them), NAME EQU value, db, dw and ds directives, and
+// expressions using numbers, labels, @ (the current address) and the usual
+// operators:
+//
+//	Start:
+//	    ld hl, Data
+//	    ld b, Data.end - Data
+//	.loop:
+//	    ld a, [hl+]
+//	    dec b
+//	    jr nz, .loop
+//	    halt
+//	Data:
+//	    db 1, 2, "abc"
+//	.end:
+package assembler
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Program is assembled machine code, and where each of its labels ended up.
+type Program struct {
+	Origin uint16
+	Bytes  []byte
+	Labels map[string]uint16
+}
+
+// Assemble assembles source into code to run from origin.
+func Assemble(origin uint16, source string) (*Program, error) {
+	a := assembler{
+		origin:  int(origin),
+		symbols: map[string]int{},
+		labels:  map[string]uint16{},
+	}
+	if err := a.firstPass(strings.Split(source, "\n")); err != nil {
+		return nil, err
+	}
+	code, err := a.secondPass()
+	if err != nil {
+		return nil, err
+	}
+	return &Program{Origin: origin, Bytes: code, Labels: a.labels}, nil
+}
+
+// MustAssemble is Assemble for source known to be good, like test programs.
+// It panics if the source doesn't assemble.
+func MustAssemble(origin uint16, source string) []byte {
+	p, err := Assemble(origin, source)
+	if err != nil {
+		panic(err)
+	}
+	return p.Bytes
+}
+
+// AssembleInstruction assembles a single instruction to run at addr, eg. to
+// patch code in a debugger.
+func AssembleInstruction(addr uint16, instruction string) ([]byte, error) {
+	if strings.Contains(instruction, "\n") {
+		return nil, errors.New("expected a single instruction")
+	}
+	p, err := Assemble(addr, instruction)
+	if err != nil {
+		return nil, err
+	}
+	return p.Bytes, nil
+}
+
+// statement is an instruction or data directive, as laid out by the first
+// pass.
+type statement struct {
+	addr    int
+	scope   string
+	inst    *instruction
+	operand expr
+	// data is for db, dw and ds - each value is width bytes long
+	data  []expr
+	width int
+}
+
+type assembler struct {
+	origin     int
+	addr       int
+	scope      string
+	symbols    map[string]int
+	labels     map[string]uint16
+	statements []statement
+}
+
+type scopedSymbols struct {
+	a     *assembler
+	scope string
+	addr  int
+}
+
+func (s scopedSymbols) lookup(name string) (int, bool) {
+	if name == "@" {
+		return s.addr, true
+	}
+	v, ok := s.a.symbols[s.a.qualify(s.scope, name)]
+	return v, ok
+}
+
+// qualify gives local labels their full name, eg. .loop after Start is
+// Start.loop.
+func (a *assembler) qualify(scope, name string) string {
+	if strings.HasPrefix(name, ".") {
+		return scope + name
+	}
+	return name
+}
+
+func (a *assembler) define(name string, value int) error {
+	if _, ok := a.symbols[name]; ok {
+		return fmt.Errorf("%s is already defined", name)
+	}
+	a.symbols[name] = value
+	return nil
+}
+
+func (a *assembler) eval(e expr) (int, error) {
+	return e.eval(scopedSymbols{a, a.scope, a.addr})
+}
+
+func (a *assembler) firstPass(lines []string) error {
+	a.addr = a.origin
+	for i, line := range lines {
+		if err := a.parseLine(line); err != nil {
+			return fmt.Errorf("line %d: %w", i+1, err)
+		}
+		if a.addr > 0x10000 {
+			return fmt.Errorf("line %d: code runs past 0xFFFF", i+1)
+		}
+	}
+	return nil
+}
+
+func (a *assembler) parseLine(line string) error {
+	line = strings.TrimSpace(stripComment(line))
+
+	// labels end in : (or :: in RGBDS, for exported labels)
+	if end := scan(line, 0, isAlphanumeric); end > 0 && end < len(line) && line[end] == ':' {
+		name := line[:end]
+		if !strings.HasPrefix(name, ".") {
+			a.scope = name
+		}
+		name = a.qualify(a.scope, name)
+		if err := a.define(name, a.addr); err != nil {
+			return err
+		}
+		a.labels[name] = uint16(a.addr)
+		line = strings.TrimSpace(strings.TrimLeft(line[end:], ":"))
+	}
+	if line == "" {
+		return nil
+	}
+
+	fields := strings.Fields(line)
+	if strings.EqualFold(fields[0], "DEF") {
+		fields = fields[1:]
+		line = strings.TrimSpace(line[3:])
+	}
+	if len(fields) > 2 && strings.EqualFold(fields[1], "EQU") {
+		value := strings.TrimSpace(strings.TrimSpace(line[len(fields[0]):])[len("EQU"):])
+		e, err := parseExpr(value)
+		if err != nil {
+			return err
+		}
+		v, err := a.eval(e)
+		if err != nil {
+			return err
+		}
+		return a.define(fields[0], v)
+	}
+
+	mnemonic, rest := line, ""
+	if i := strings.IndexAny(line, " \t"); i >= 0 {
+		mnemonic, rest = line[:i], line[i:]
+	}
+	mnemonic = strings.ToUpper(mnemonic)
+	operands, err := splitOperands(rest)
+	if err != nil {
+		return err
+	}
+	switch mnemonic {
+	case "DB":
+		return a.data(operands, 1)
+	case "DW":
+		return a.data(operands, 2)
+	case "DS":
+		return a.space(operands)
+	}
+	return a.instruction(mnemonic, operands)
+}
+
+func (a *assembler) instruction(mnemonic string, operands []string) error {
+	shapes := make([]string, len(operands))
+	var operand expr
+	for i, o := range operands {
+		shape, e, err := a.parseOperand(mnemonic, i, o)
+		if err != nil {
+			return err
+		}
+		shapes[i] = shape
+		if e != nil {
+			if operand != nil {
+				return errors.New("only one operand can be a value")
+			}
+			operand = e
+		}
+	}
+
+	key := joinInstruction(mnemonic, shapes)
+	inst, ok := instructions[key]
+	if !ok {
+		return fmt.Errorf("unknown instruction %s", key)
+	}
+	a.statements = append(a.statements, statement{addr: a.addr, scope: a.scope, inst: &inst, operand: operand})
+	a.addr += inst.size()
+	return nil
+}
+
+// registers are the operands written as themselves, along with NZ, Z, NC and
+// C conditions.
+var registers = map[string]bool{
+	"A": true, "B": true, "C": true, "D": true, "E": true, "H": true, "L": true,
+	"AF": true, "BC": true, "DE": true, "HL": true, "SP": true,
+	"NZ": true, "Z": true, "NC": true,
+	"[BC]": true, "[DE]": true, "[HL]": true, "[HL+]": true, "[HL-]": true, "[C]": true,
+}
+
+var indirectAliases = map[string]string{
+	"[HLI]":      "[HL+]",
+	"[HLD]":      "[HL-]",
+	"[$FF00+C]":  "[C]",
+	"[0XFF00+C]": "[C]",
+}
+
+// parseOperand works out an operand's part of the instructions key, along
+// with its value if it has one.
+func (a *assembler) parseOperand(mnemonic string, index int, operand string) (string, expr, error) {
+	compact := strings.ToUpper(strings.Join(strings.Fields(operand), ""))
+	if alias, ok := indirectAliases[compact]; ok {
+		compact = alias
+	}
+	if registers[compact] {
+		return compact, nil, nil
+	}
+
+	// bit numbers and RST vectors are part of the opcode, so need to be
+	// known on the first pass
+	if index == 0 && (mnemonic == "BIT" || mnemonic == "RES" || mnemonic == "SET" || mnemonic == "RST") {
+		e, err := parseExpr(operand)
+		if err != nil {
+			return "", nil, err
+		}
+		v, err := a.eval(e)
+		if err != nil {
+			return "", nil, err
+		}
+		return fmt.Sprintf("%X", v), nil, nil
+	}
+
+	shape := "#"
+	if strings.HasPrefix(compact, "[") && strings.HasSuffix(compact, "]") {
+		shape = "[#]"
+		operand = strings.TrimSpace(operand)
+		operand = operand[1 : len(operand)-1]
+	} else if strings.HasPrefix(compact, "SP+") || strings.HasPrefix(compact, "SP-") {
+		shape = "SP+#"
+		operand = "0" + strings.TrimSpace(operand)[2:]
+	}
+	e, err := parseExpr(operand)
+	return shape, e, err
+}
+
+func (a *assembler) data(operands []string, width int) error {
+	if len(operands) == 0 {
+		return errors.New("missing values")
+	}
+	s := statement{addr: a.addr, scope: a.scope, width: width}
+	for _, o := range operands {
+		if width == 1 && len(o) >= 2 && o[0] == '"' && o[len(o)-1] == '"' {
+			for _, c := range []byte(o[1 : len(o)-1]) {
+				s.data = append(s.data, number(c))
+			}
+			continue
+		}
+		e, err := parseExpr(o)
+		if err != nil {
+			return err
+		}
+		s.data = append(s.data, e)
+	}
+	a.statements = append(a.statements, s)
+	a.addr += len(s.data) * width
+	return nil
+}
+
+// space handles ds count[, fill]. The count needs to be known on the first
+// pass.
+func (a *assembler) space(operands []string) error {
+	if len(operands) == 0 || len(operands) > 2 {
+		return errors.New("expected ds count[, fill]")
+	}
+	values := make([]expr, len(operands))
+	for i, o := range operands {
+		e, err := parseExpr(o)
+		if err != nil {
+			return err
+		}
+		values[i] = e
+	}
+	count, err := a.eval(values[0])
+	if err != nil {
+		return err
+	}
+	if count < 0 {
+		return fmt.Errorf("negative ds count %d", count)
+	}
+	fill := expr(number(0))
+	if len(values) == 2 {
+		fill = values[1]
+	}
+	s := statement{addr: a.addr, scope: a.scope, width: 1}
+	for i := 0; i < count; i++ {
+		s.data = append(s.data, fill)
+	}
+	a.statements = append(a.statements, s)
+	a.addr += count
+	return nil
+}
+
+func (a *assembler) secondPass() ([]byte, error) {
+	code := make([]byte, 0, a.addr-a.origin)
+	for _, s := range a.statements {
+		syms := scopedSymbols{a, s.scope, s.addr}
+		var err error
+		if s.inst != nil {
+			code, err = encodeInstruction(code, s, syms)
+		} else {
+			code, err = encodeData(code, s, syms)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("at %04X: %w", s.addr, err)
+		}
+	}
+	return code, nil
+}
+
+func encodeInstruction(code []byte, s statement, syms symbols) ([]byte, error) {
+	inst := s.inst
+	if inst.extended {
+		code = append(code, 0xCB)
+	}
+	code = append(code, inst.code)
+
+	if inst.encoding == paddingOperand {
+		return append(code, 0x00), nil
+	}
+	if s.operand == nil {
+		return code, nil
+	}
+	v, err := s.operand.eval(syms)
+	if err != nil {
+		return nil, err
+	}
+	switch inst.encoding {
+	case byteOperand:
+		if v < -0x80 || v > 0xFF {
+			return nil, fmt.Errorf("%d doesn't fit in a byte", v)
+		}
+		code = append(code, byte(v))
+	case wordOperand:
+		if v < -0x8000 || v > 0xFFFF {
+			return nil, fmt.Errorf("%d doesn't fit in a word", v)
+		}
+		code = append(code, byte(v), byte(v>>8))
+	case signedOperand:
+		if v < -0x80 || v > 0x7F {
+			return nil, fmt.Errorf("offset %d is out of range", v)
+		}
+		code = append(code, byte(v))
+	case relativeOperand:
+		offset := v - (s.addr + inst.size())
+		if offset < -0x80 || offset > 0x7F {
+			return nil, fmt.Errorf("jump to %04X is out of range", v)
+		}
+		code = append(code, byte(offset))
+	case highOperand:
+		if v >= 0xFF00 && v <= 0xFFFF {
+			v -= 0xFF00
+		} else if v < 0 || v > 0xFF {
+			return nil, fmt.Errorf("%04X isn't in high ram (FF00-FFFF)", v)
+		}
+		code = append(code, byte(v))
+	}
+	return code, nil
+}
+
+func encodeData(code []byte, s statement, syms symbols) ([]byte, error) {
+	for _, e := range s.data {
+		v, err := e.eval(syms)
+		if err != nil {
+			return nil, err
+		}
+		if s.width == 1 {
+			if v < -0x80 || v > 0xFF {
+				return nil, fmt.Errorf("%d doesn't fit in a byte", v)
+			}
+			code = append(code, byte(v))
+		} else {
+			if v < -0x8000 || v > 0xFFFF {
+				return nil, fmt.Errorf("%d doesn't fit in a word", v)
+			}
+			code = append(code, byte(v), byte(v>>8))
+		}
+	}
+	return code, nil
+}
+
+func stripComment(line string) string {
+	inString := false
+	for i := 0; i < len(line); i++ {
+		switch line[i] {
+		case '"':
+			inString = !inString
+		case ';':
+			if !inString {
+				return line[:i]
+			}
+		}
+	}
+	return line
+}
+
+// splitOperands splits on commas that aren't inside a string, brackets or a
+// character literal.
+func splitOperands(s string) ([]string, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return nil, nil
+	}
+	operands := make([]string, 0)
+	depth := 0
+	inString := false
+	start := 0
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; {
+		case c == '"':
+			inString = !inString
+		case inString:
+		case c == '\'' && i+2 < len(s) && s[i+2] == '\'':
+			i += 2
+		case c == '(' || c == '[':
+			depth++
+		case c == ')' || c == ']':
+			depth--
+		case c == ',' && depth == 0:
+			operands = append(operands, strings.TrimSpace(s[start:i]))
+			start = i + 1
+		}
+	}
+	if inString || depth != 0 {
+		return nil, fmt.Errorf("unbalanced quotes or brackets in %q", s)
+	}
+	operands = append(operands, strings.TrimSpace(s[start:]))
+	for _, o := range operands {
+		if o == "" {
+			return nil, fmt.Errorf("missing operand in %q", s)
+		}
+	}
+	return operands, nil
+}
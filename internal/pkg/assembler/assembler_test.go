@@ -0,0 +1,169 @@
+package assembler
+
+import (
+	"github.com/mr-tim/goboye/internal/pkg/cpu"
+	"github.com/mr-tim/goboye/internal/pkg/memory"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestEveryOpcodeAssembles(t *testing.T) {
+	base := map[byte]bool{}
+	extended := map[byte]bool{}
+	for _, inst := range instructions {
+		if inst.extended {
+			extended[inst.code] = true
+		} else {
+			base[inst.code] = true
+		}
+	}
+	// all but the 11 illegal opcodes and the CB prefix
+	assert.Equal(t, 244, len(base))
+	assert.Equal(t, 256, len(extended))
+}
+
+func TestAssembleInstruction(t *testing.T) {
+	tests := []struct {
+		source   string
+		expected []byte
+	}{
+		{"nop", []byte{0x00}},
+		{"ld a, b", []byte{0x78}},
+		{"LD B,$12", []byte{0x06, 0x12}},
+		{"ld hl, $C000", []byte{0x21, 0x00, 0xC0}},
+		{"ld [hl], 5", []byte{0x36, 0x05}},
+		{"ld a, [hl+]", []byte{0x2A}},
+		{"ld a, [hli]", []byte{0x2A}},
+		{"ld [hl-], a", []byte{0x32}},
+		{"ldd [hl], a", []byte{0x32}},
+		{"ld a, [$C123]", []byte{0xFA, 0x23, 0xC1}},
+		{"ld [$C123], sp", []byte{0x08, 0x23, 0xC1}},
+		{"ldh [$FF44], a", []byte{0xE0, 0x44}},
+		{"ldh a, [$80]", []byte{0xF0, 0x80}},
+		{"ld [c], a", []byte{0xE2}},
+		{"ldh a, [c]", []byte{0xF2}},
+		{"ld a, [$FF00+c]", []byte{0xF2}},
+		{"ld hl, sp+2", []byte{0xF8, 0x02}},
+		{"ld hl, sp-2", []byte{0xF8, 0xFE}},
+		{"add sp, -1", []byte{0xE8, 0xFF}},
+		{"add a, b", []byte{0x80}},
+		{"add b", []byte{0x80}},
+		{"sub a, 3", []byte{0xD6, 0x03}},
+		{"sub 3", []byte{0xD6, 0x03}},
+		{"and a", []byte{0xA7}},
+		{"cp [hl]", []byte{0xBE}},
+		{"add hl, de", []byte{0x19}},
+		{"jp c, $1234", []byte{0xDA, 0x34, 0x12}},
+		{"jp hl", []byte{0xE9}},
+		{"jr @", []byte{0x18, 0xFE}},
+		{"call nz, $4000", []byte{0xC4, 0x00, 0x40}},
+		{"ret z", []byte{0xC8}},
+		{"rst $38", []byte{0xFF}},
+		{"rst 8", []byte{0xCF}},
+		{"rlca", []byte{0x07}},
+		{"rlc a", []byte{0xCB, 0x07}},
+		{"rra", []byte{0x1F}},
+		{"swap a", []byte{0xCB, 0x37}},
+		{"bit 7, h", []byte{0xCB, 0x7C}},
+		{"res 0, [hl]", []byte{0xCB, 0x86}},
+		{"set 3, a", []byte{0xCB, 0xDF}},
+		{"stop", []byte{0x10, 0x00}},
+		{"ld a, LOW($1234) + HIGH($1234)", []byte{0x3E, 0x46}},
+		{"ld a, 'A'", []byte{0x3E, 0x41}},
+		{"ld a, %1010 | 1 << 4", []byte{0x3E, 0x1A}},
+		{"ld a, (2 + 3) * 4 % 7", []byte{0x3E, 0x06}},
+	}
+	for _, test := range tests {
+		t.Run(test.source, func(t *testing.T) {
+			code, err := AssembleInstruction(0x0100, test.source)
+			assert.NoError(t, err)
+			assert.Equal(t, test.expected, code)
+		})
+	}
+}
+
+func TestAssembleLabels(t *testing.T) {
+	p, err := Assemble(0x0150, `
+Main:
+    ld hl, Data      ; forward reference
+    ld b, Data.end - Data
+.loop:
+    dec b
+    jr nz, .loop
+    jp Main
+Data::
+    db 1, 2, "ab"
+    dw $1234, Main
+    ds 2, $FF
+.end:
+`)
+	assert.NoError(t, err)
+	assert.Equal(t, uint16(0x0150), p.Labels["Main"])
+	assert.Equal(t, uint16(0x0155), p.Labels["Main.loop"])
+	assert.Equal(t, uint16(0x015B), p.Labels["Data"])
+	assert.Equal(t, uint16(0x0165), p.Labels["Data.end"])
+	assert.Equal(t, []byte{
+		0x21, 0x5B, 0x01,
+		0x06, 0x0A,
+		0x05,
+		0x20, 0xFD,
+		0xC3, 0x50, 0x01,
+		0x01, 0x02, 'a', 'b',
+		0x34, 0x12, 0x50, 0x01,
+		0xFF, 0xFF,
+	}, p.Bytes)
+}
+
+func TestAssembleEqu(t *testing.T) {
+	code, err := Assemble(0, `
+rLY EQU $FF44
+DEF COUNT EQU 3 * 2
+    ldh a, [rLY]
+    ld b, COUNT
+`)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{0xF0, 0x44, 0x06, 0x06}, code.Bytes)
+}
+
+func TestAssembleErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		source string
+		err    string
+	}{
+		{"unknown instruction", "ld b, [de]", "line 1: unknown instruction LD B,[DE]"},
+		{"undefined label", "nop\njp Nowhere", "at 0001: undefined symbol Nowhere"},
+		{"duplicate label", "Start:\nStart:", "line 2: Start is already defined"},
+		{"byte overflow", "ld a, 256", "at 0000: 256 doesn't fit in a byte"},
+		{"jump out of range", "jr $1000", "at 0000: jump to 1000 is out of range"},
+		{"not high ram", "ldh a, [$C000]", "at 0000: C000 isn't in high ram (FF00-FFFF)"},
+		{"bad number", "ld a, $G", `line 1: bad number "$G"`},
+		{"illegal opcode", "xx", "line 1: unknown instruction XX"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := Assemble(0, test.source)
+			assert.EqualError(t, err, test.err)
+		})
+	}
+}
+
+func TestAssembledProgramRuns(t *testing.T) {
+	// sum 1 to 10 into A
+	rom := MustAssemble(0x0000, `
+    xor a
+    ld b, 10
+.loop:
+    add a, b
+    dec b
+    jr nz, .loop
+    halt
+`)
+	m := memory.NewControllerWithBytes(rom)
+	m.BootRomRegister.Write(0x01)
+	p := cpu.NewProcessor(&m)
+	for !p.IsHalted() {
+		p.DoNextInstruction()
+	}
+	assert.Equal(t, uint8(55), p.GetRegister(cpu.RegisterA))
+}
@@ -0,0 +1,305 @@
+package assembler
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// symbols resolves the names used in expressions. @ is the address of the
+// current instruction.
+type symbols interface {
+	lookup(name string) (int, bool)
+}
+
+// errUndefined is returned for expressions using a symbol that hasn't been
+// defined (yet - labels further down are only known on the second pass).
+var errUndefined = errors.New("undefined symbol")
+
+type expr interface {
+	eval(s symbols) (int, error)
+}
+
+type number int
+
+func (n number) eval(s symbols) (int, error) {
+	return int(n), nil
+}
+
+type symbol string
+
+func (n symbol) eval(s symbols) (int, error) {
+	if v, ok := s.lookup(string(n)); ok {
+		return v, nil
+	}
+	return 0, fmt.Errorf("%w %s", errUndefined, string(n))
+}
+
+type unary struct {
+	op      string
+	operand expr
+}
+
+func (u unary) eval(s symbols) (int, error) {
+	v, err := u.operand.eval(s)
+	if err != nil {
+		return 0, err
+	}
+	switch u.op {
+	case "-":
+		return -v, nil
+	case "~":
+		return ^v, nil
+	case "!":
+		if v == 0 {
+			return 1, nil
+		}
+		return 0, nil
+	case "HIGH":
+		return (v >> 8) & 0xFF, nil
+	case "LOW":
+		return v & 0xFF, nil
+	}
+	return v, nil
+}
+
+type binary struct {
+	op          string
+	left, right expr
+}
+
+func (b binary) eval(s symbols) (int, error) {
+	l, err := b.left.eval(s)
+	if err != nil {
+		return 0, err
+	}
+	r, err := b.right.eval(s)
+	if err != nil {
+		return 0, err
+	}
+	switch b.op {
+	case "+":
+		return l + r, nil
+	case "-":
+		return l - r, nil
+	case "*":
+		return l * r, nil
+	case "/", "%":
+		if r == 0 {
+			return 0, errors.New("division by zero")
+		}
+		if b.op == "/" {
+			return l / r, nil
+		}
+		return l % r, nil
+	case "&":
+		return l & r, nil
+	case "|":
+		return l | r, nil
+	case "^":
+		return l ^ r, nil
+	case "<<":
+		return l << uint(r), nil
+	case ">>":
+		return l >> uint(r), nil
+	}
+	return 0, fmt.Errorf("unknown operator %s", b.op)
+}
+
+// precedence of the binary operators, loosest first.
+var precedence = [][]string{
+	{"|"},
+	{"^"},
+	{"&"},
+	{"<<", ">>"},
+	{"+", "-"},
+	{"*", "/", "%"},
+}
+
+// parseExpr parses an expression: numbers ($FF, 0xFF, %1010, 0b1010, 42 or
+// 'c'), symbols, @, the usual C operators, and HIGH() and LOW().
+func parseExpr(s string) (expr, error) {
+	tokens, err := tokenize(s)
+	if err != nil {
+		return nil, err
+	}
+	p := exprParser{tokens: tokens}
+	e, err := p.parseBinary(0)
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.tokens) {
+		return nil, fmt.Errorf("unexpected %q in %q", p.tokens[p.pos], s)
+	}
+	return e, nil
+}
+
+type exprParser struct {
+	tokens []string
+	pos    int
+}
+
+func (p *exprParser) peek() string {
+	if p.pos < len(p.tokens) {
+		return p.tokens[p.pos]
+	}
+	return ""
+}
+
+func (p *exprParser) next() string {
+	t := p.peek()
+	p.pos++
+	return t
+}
+
+func (p *exprParser) parseBinary(level int) (expr, error) {
+	if level == len(precedence) {
+		return p.parseUnary()
+	}
+	left, err := p.parseBinary(level + 1)
+	if err != nil {
+		return nil, err
+	}
+	for isOneOf(p.peek(), precedence[level]) {
+		op := p.next()
+		right, err := p.parseBinary(level + 1)
+		if err != nil {
+			return nil, err
+		}
+		left = binary{op, left, right}
+	}
+	return left, nil
+}
+
+func (p *exprParser) parseUnary() (expr, error) {
+	t := p.next()
+	switch {
+	case t == "":
+		return nil, errors.New("missing value")
+	case t == "-" || t == "+" || t == "~" || t == "!":
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return unary{t, operand}, nil
+	case t == "(":
+		e, err := p.parseBinary(0)
+		if err != nil {
+			return nil, err
+		}
+		if p.next() != ")" {
+			return nil, errors.New("missing )")
+		}
+		return e, nil
+	case strings.EqualFold(t, "HIGH") || strings.EqualFold(t, "LOW"):
+		if p.peek() != "(" {
+			return nil, fmt.Errorf("%s needs an argument in brackets", t)
+		}
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return unary{strings.ToUpper(t), operand}, nil
+	case t[0] == '\'':
+		return number(t[1]), nil
+	case isSymbolStart(t[0]):
+		return symbol(t), nil
+	}
+	v, err := parseNumber(t)
+	if err != nil {
+		return nil, err
+	}
+	return number(v), nil
+}
+
+func parseNumber(t string) (int, error) {
+	base := 10
+	digits := t
+	switch {
+	case t[0] == '$':
+		base, digits = 16, t[1:]
+	case t[0] == '%':
+		base, digits = 2, t[1:]
+	case len(t) > 2 && (t[:2] == "0x" || t[:2] == "0X"):
+		base, digits = 16, t[2:]
+	case len(t) > 2 && (t[:2] == "0b" || t[:2] == "0B"):
+		base, digits = 2, t[2:]
+	}
+	v, err := strconv.ParseInt(strings.ReplaceAll(digits, "_", ""), base, 32)
+	if err != nil {
+		return 0, fmt.Errorf("bad number %q", t)
+	}
+	return int(v), nil
+}
+
+func tokenize(s string) ([]string, error) {
+	tokens := make([]string, 0)
+	for i := 0; i < len(s); {
+		c := s[i]
+		switch {
+		case c == ' ' || c == '\t':
+			i++
+		case c == '<' || c == '>':
+			if i+1 >= len(s) || s[i+1] != c {
+				return nil, fmt.Errorf("unexpected %q in %q", c, s)
+			}
+			tokens = append(tokens, s[i:i+2])
+			i += 2
+		case c == '%' && i+1 < len(s) && (s[i+1] == '0' || s[i+1] == '1') && !followsValue(tokens):
+			// a binary number, rather than modulo
+			j := scan(s, i+1, isAlphanumeric)
+			tokens = append(tokens, s[i:j])
+			i = j
+		case strings.IndexByte("+-*/%&|^~!()", c) >= 0:
+			tokens = append(tokens, string(c))
+			i++
+		case c == '\'':
+			if i+2 >= len(s) || s[i+2] != '\'' {
+				return nil, fmt.Errorf("bad character literal in %q", s)
+			}
+			tokens = append(tokens, s[i:i+3])
+			i += 3
+		case c == '$' || isAlphanumeric(c) || isSymbolStart(c):
+			j := scan(s, i+1, isAlphanumeric)
+			tokens = append(tokens, s[i:j])
+			i = j
+		default:
+			return nil, fmt.Errorf("unexpected %q in %q", c, s)
+		}
+	}
+	return tokens, nil
+}
+
+// followsValue reports whether the next token is in operator position.
+func followsValue(tokens []string) bool {
+	if len(tokens) == 0 {
+		return false
+	}
+	last := tokens[len(tokens)-1]
+	return last == ")" || isAlphanumeric(last[0]) || isSymbolStart(last[0]) || last[0] == '$' || last[0] == '\''
+}
+
+func scan(s string, i int, f func(byte) bool) int {
+	for i < len(s) && f(s[i]) {
+		i++
+	}
+	return i
+}
+
+func isSymbolStart(c byte) bool {
+	return c == '_' || c == '.' || c == '@' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
+
+func isAlphanumeric(c byte) bool {
+	return isSymbolStart(c) || c >= '0' && c <= '9' || c == '#'
+}
+
+func isOneOf(t string, options []string) bool {
+	for _, o := range options {
+		if t == o {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,163 @@
+package assembler
+
+import (
+	"fmt"
+	"github.com/mr-tim/goboye/internal/pkg/cpu"
+	"strings"
+)
+
+// operandEncoding is how an instruction's immediate operand is written after
+// its opcode.
+type operandEncoding int
+
+const (
+	noOperand operandEncoding = iota
+	byteOperand
+	wordOperand
+	// signedOperand is an offset from SP, eg. ADD SP,e8
+	signedOperand
+	// relativeOperand is JR's target, written as an offset from the next
+	// instruction
+	relativeOperand
+	// highOperand is LDH's address, which must be in 0xFF00-0xFFFF
+	highOperand
+	// paddingOperand is the unused byte after STOP
+	paddingOperand
+)
+
+type instruction struct {
+	extended bool
+	code     byte
+	encoding operandEncoding
+}
+
+func (i instruction) size() int {
+	size := 1
+	if i.extended {
+		size++
+	}
+	switch i.encoding {
+	case noOperand:
+	case wordOperand:
+		size += 2
+	default:
+		size++
+	}
+	return size
+}
+
+// rgbdsSyntax is the RGBDS way of writing the base opcodes whose disassembly
+// is written differently.
+var rgbdsSyntax = map[string]string{
+	"RLC A":      "RLCA",
+	"RRC A":      "RRCA",
+	"RL A":       "RLA",
+	"RR A":       "RRA",
+	"LDI (HL),A": "LD [HL+],A",
+	"LDI A,(HL)": "LD A,[HL+]",
+	"LDD (HL),A": "LD [HL-],A",
+	"LDD A,(HL)": "LD A,[HL-]",
+	"LDH (C),A":  "LD [C],A",
+	"LDH A,C":    "LD A,[C]",
+	"LDHL SP,d":  "LD HL,SP+d",
+	"JP (HL)":    "JP HL",
+}
+
+// aliases are other spellings RGBDS accepts.
+var aliases = map[string]string{
+	"LDI [HL],A": "LD [HL+],A",
+	"LDI A,[HL]": "LD A,[HL+]",
+	"LDD [HL],A": "LD [HL-],A",
+	"LDD A,[HL]": "LD A,[HL-]",
+	"LDH [C],A":  "LD [C],A",
+	"LDH A,[C]":  "LD A,[C]",
+	"LDHL SP,#":  "LD HL,SP+#",
+	"JP [HL]":    "JP HL",
+}
+
+// aluOps can leave out A as their first operand.
+var aluOps = map[string]bool{
+	"ADD": true, "ADC": true, "SUB": true, "SBC": true,
+	"AND": true, "XOR": true, "OR": true, "CP": true,
+}
+
+// instructions are keyed by mnemonic and operands, with immediate values
+// written as #, eg. "LD A,[#]".
+var instructions = buildInstructions()
+
+func buildInstructions() map[string]instruction {
+	result := map[string]instruction{}
+	add := func(o cpu.Opcode, extended bool) {
+		if o.Disassembly() == "XX" || o.Code() == cpu.OpcodeExtOps.Code() && !extended {
+			return
+		}
+		key, encoding := instructionKey(o, extended)
+		if _, ok := result[key]; ok {
+			panic(fmt.Sprintf("two opcodes assemble from %q", key))
+		}
+		result[key] = instruction{extended, o.Code(), encoding}
+	}
+	for b := 0; b < 0x100; b++ {
+		add(cpu.LookupOpcode(byte(b)), false)
+		add(cpu.LookupExtOpcode(byte(b)), true)
+	}
+	for alias, key := range aliases {
+		result[alias] = result[key]
+	}
+	return result
+}
+
+// instructionKey turns an opcode's disassembly into its instructions key.
+func instructionKey(o cpu.Opcode, extended bool) (string, operandEncoding) {
+	d := o.Disassembly()
+	if s, ok := rgbdsSyntax[d]; ok && !extended {
+		d = s
+	}
+	mnemonic, operands := splitInstruction(d)
+
+	encoding := noOperand
+	for i, operand := range operands {
+		switch operand {
+		case "n":
+			encoding, operand = byteOperand, "#"
+		case "nn":
+			encoding, operand = wordOperand, "#"
+		case "(n)":
+			encoding, operand = highOperand, "[#]"
+		case "(nn)":
+			encoding, operand = wordOperand, "[#]"
+		case "d":
+			encoding, operand = signedOperand, "#"
+		case "SP+d":
+			encoding, operand = signedOperand, "SP+#"
+		default:
+			operand = strings.NewReplacer("(", "[", ")", "]").Replace(operand)
+		}
+		operands[i] = operand
+	}
+	if mnemonic == "JR" {
+		encoding = relativeOperand
+	}
+	if encoding == noOperand && o.PayloadLength() == 1 {
+		encoding = paddingOperand
+	}
+	return joinInstruction(mnemonic, operands), encoding
+}
+
+func splitInstruction(s string) (string, []string) {
+	mnemonic, rest, _ := strings.Cut(s, " ")
+	if rest == "" {
+		return mnemonic, nil
+	}
+	return mnemonic, strings.Split(rest, ",")
+}
+
+func joinInstruction(mnemonic string, operands []string) string {
+	if aluOps[mnemonic] && len(operands) == 1 {
+		operands = append([]string{"A"}, operands...)
+	}
+	if len(operands) == 0 {
+		return mnemonic
+	}
+	return mnemonic + " " + strings.Join(operands, ",")
+}
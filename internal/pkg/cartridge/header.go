@@ -0,0 +1,244 @@
+package cartridge
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+/*
+	Cartridge header - 0x0100-0x014F
+
+	0x0100-0x0103 - entry point
+	0x0104-0x0133 - nintendo logo
+	0x0134-0x0143 - title (upper case ascii, padded with 0x00)
+		0x013F-0x0142 - manufacturer code (newer carts)
+		0x0143        - CGB flag (newer carts)
+	0x0144-0x0145 - new licensee code (two ascii characters)
+	0x0146        - SGB flag (0x03 for SGB support)
+	0x0147        - cartridge type
+	0x0148        - rom size (32KiB << n)
+	0x0149        - ram size
+	0x014A        - destination code (0x00 japan, 0x01 overseas)
+	0x014B        - old licensee code (0x33 means use the new code)
+	0x014C        - mask rom version
+	0x014D        - header checksum over 0x0134-0x014C
+	0x014E-0x014F - global checksum (big endian) over the whole rom, minus these two bytes
+*/
+
+const HeaderEnd = 0x0150
+
+const (
+	titleAddr            = 0x0134
+	manufacturerAddr     = 0x013F
+	cgbFlagAddr          = 0x0143
+	newLicenseeAddr      = 0x0144
+	sgbFlagAddr          = 0x0146
+	typeAddr             = 0x0147
+	romSizeAddr          = 0x0148
+	ramSizeAddr          = 0x0149
+	destinationAddr      = 0x014A
+	oldLicenseeAddr      = 0x014B
+	versionAddr          = 0x014C
+	headerChecksumAddr   = 0x014D
+	globalChecksumAddr   = 0x014E
+	useNewLicenseeMarker = 0x33
+)
+
+var ErrRomTooShort = errors.New("rom is too short to contain a cartridge header")
+
+type CgbSupport byte
+
+const (
+	CgbUnsupported CgbSupport = 0
+	CgbEnhanced    CgbSupport = 1
+	CgbOnly        CgbSupport = 2
+)
+
+func (c CgbSupport) String() string {
+	switch c {
+	case CgbEnhanced:
+		return "CGB enhanced"
+	case CgbOnly:
+		return "CGB only"
+	default:
+		return "DMG"
+	}
+}
+
+type Header struct {
+	Title            string
+	ManufacturerCode string
+	CgbFlag          byte
+	SgbFlag          byte
+	Type             Type
+	RomSizeCode      byte
+	RamSizeCode      byte
+	DestinationCode  byte
+	OldLicensee      byte
+	NewLicensee      string
+	Version          byte
+	HeaderChecksum   byte
+	GlobalChecksum   uint16
+
+	computedHeaderChecksum byte
+	computedGlobalChecksum uint16
+}
+
+func Parse(rom []byte) (Header, error) {
+	if len(rom) < HeaderEnd {
+		return Header{}, ErrRomTooShort
+	}
+
+	h := Header{
+		CgbFlag:         rom[cgbFlagAddr],
+		SgbFlag:         rom[sgbFlagAddr],
+		Type:            Type(rom[typeAddr]),
+		RomSizeCode:     rom[romSizeAddr],
+		RamSizeCode:     rom[ramSizeAddr],
+		DestinationCode: rom[destinationAddr],
+		OldLicensee:     rom[oldLicenseeAddr],
+		NewLicensee:     string(rom[newLicenseeAddr : newLicenseeAddr+2]),
+		Version:         rom[versionAddr],
+		HeaderChecksum:  rom[headerChecksumAddr],
+		GlobalChecksum:  uint16(rom[globalChecksumAddr])<<8 | uint16(rom[globalChecksumAddr+1]),
+	}
+
+	titleEnd := cgbFlagAddr + 1
+	if h.CgbSupport() != CgbUnsupported {
+		titleEnd = cgbFlagAddr
+		if isManufacturerCode(rom[manufacturerAddr:cgbFlagAddr]) {
+			h.ManufacturerCode = string(rom[manufacturerAddr:cgbFlagAddr])
+			titleEnd = manufacturerAddr
+		}
+	}
+	h.Title = decodeTitle(rom[titleAddr:titleEnd])
+
+	for i := titleAddr; i < headerChecksumAddr; i++ {
+		h.computedHeaderChecksum = h.computedHeaderChecksum - rom[i] - 1
+	}
+	for i, b := range rom {
+		if i != globalChecksumAddr && i != globalChecksumAddr+1 {
+			h.computedGlobalChecksum += uint16(b)
+		}
+	}
+
+	return h, nil
+}
+
+func decodeTitle(bs []byte) string {
+	title := strings.Builder{}
+	for _, b := range bs {
+		if b == 0x00 {
+			break
+		}
+		title.WriteByte(b)
+	}
+	return strings.TrimSpace(title.String())
+}
+
+func isManufacturerCode(bs []byte) bool {
+	for _, b := range bs {
+		if b < 'A' || b > 'Z' {
+			return false
+		}
+	}
+	return true
+}
+
+func (h Header) CgbSupport() CgbSupport {
+	switch h.CgbFlag {
+	case 0x80:
+		return CgbEnhanced
+	case 0xC0:
+		return CgbOnly
+	default:
+		return CgbUnsupported
+	}
+}
+
+func (h Header) SupportsSgb() bool {
+	return h.SgbFlag == 0x03
+}
+
+// RomSize is the rom size in bytes, or 0 if the size code is unknown.
+func (h Header) RomSize() int {
+	switch {
+	case h.RomSizeCode <= 0x08:
+		return 0x8000 << h.RomSizeCode
+	case h.RomSizeCode == 0x52:
+		return 72 * 0x4000
+	case h.RomSizeCode == 0x53:
+		return 80 * 0x4000
+	case h.RomSizeCode == 0x54:
+		return 96 * 0x4000
+	default:
+		return 0
+	}
+}
+
+// RamSize is the external ram size in bytes. MBC2 carts report 0 here - their
+// ram is built into the mapper.
+func (h Header) RamSize() int {
+	switch h.RamSizeCode {
+	case 0x01:
+		return 0x0800
+	case 0x02:
+		return 0x2000
+	case 0x03:
+		return 0x8000
+	case 0x04:
+		return 0x20000
+	case 0x05:
+		return 0x10000
+	default:
+		return 0
+	}
+}
+
+func (h Header) IsJapanese() bool {
+	return h.DestinationCode == 0x00
+}
+
+// LicenseeCode is the publisher code, taken from the new licensee field when
+// the old one says to use it.
+func (h Header) LicenseeCode() string {
+	if h.OldLicensee == useNewLicenseeMarker {
+		return h.NewLicensee
+	}
+	return fmt.Sprintf("%02X", h.OldLicensee)
+}
+
+func (h Header) Licensee() string {
+	var name string
+	var ok bool
+	if h.OldLicensee == useNewLicenseeMarker {
+		name, ok = newLicensees[h.NewLicensee]
+	} else {
+		name, ok = oldLicensees[h.OldLicensee]
+	}
+	if !ok {
+		return "Unknown"
+	}
+	return name
+}
+
+func (h Header) ComputedHeaderChecksum() byte {
+	return h.computedHeaderChecksum
+}
+
+func (h Header) ComputedGlobalChecksum() uint16 {
+	return h.computedGlobalChecksum
+}
+
+// IsHeaderChecksumValid checks the header checksum. The boot rom refuses to
+// start a cartridge when this doesn't match.
+func (h Header) IsHeaderChecksumValid() bool {
+	return h.HeaderChecksum == h.computedHeaderChecksum
+}
+
+// IsGlobalChecksumValid checks the global checksum. Real hardware never
+// verifies this one, so plenty of homebrew gets it wrong.
+func (h Header) IsGlobalChecksumValid() bool {
+	return h.GlobalChecksum == h.computedGlobalChecksum
+}
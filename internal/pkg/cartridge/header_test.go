@@ -0,0 +1,85 @@
+package cartridge
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func testRom() []byte {
+	rom := make([]byte, 0x8000)
+	copy(rom[titleAddr:], "TESTGAME")
+	rom[typeAddr] = 0x13
+	rom[romSizeAddr] = 0x05
+	rom[ramSizeAddr] = 0x03
+	rom[destinationAddr] = 0x01
+	rom[oldLicenseeAddr] = 0x01
+	rom[versionAddr] = 0x02
+	return rom
+}
+
+func withChecksums(rom []byte) []byte {
+	h, _ := Parse(rom)
+	rom[headerChecksumAddr] = h.ComputedHeaderChecksum()
+	// the header checksum byte is part of the global checksum
+	h, _ = Parse(rom)
+	rom[globalChecksumAddr] = byte(h.ComputedGlobalChecksum() >> 8)
+	rom[globalChecksumAddr+1] = byte(h.ComputedGlobalChecksum())
+	return rom
+}
+
+func TestParseHeader(t *testing.T) {
+	h, err := Parse(withChecksums(testRom()))
+
+	assert.Nil(t, err)
+	assert.Equal(t, "TESTGAME", h.Title)
+	assert.Equal(t, "", h.ManufacturerCode)
+	assert.Equal(t, CgbUnsupported, h.CgbSupport())
+	assert.False(t, h.SupportsSgb())
+	assert.Equal(t, "MBC3+RAM+BATTERY", h.Type.String())
+	assert.Equal(t, Mbc3, h.Type.Mapper())
+	assert.True(t, h.Type.HasBattery())
+	assert.False(t, h.Type.HasTimer())
+	assert.Equal(t, 1024*1024, h.RomSize())
+	assert.Equal(t, 32*1024, h.RamSize())
+	assert.False(t, h.IsJapanese())
+	assert.Equal(t, "01", h.LicenseeCode())
+	assert.Equal(t, "Nintendo", h.Licensee())
+	assert.Equal(t, uint8(0x02), h.Version)
+	assert.True(t, h.IsHeaderChecksumValid())
+	assert.True(t, h.IsGlobalChecksumValid())
+}
+
+func TestParseCgbHeader(t *testing.T) {
+	rom := testRom()
+	copy(rom[titleAddr:], "POKEMON_GLDAAUE")
+	rom[cgbFlagAddr] = 0x80
+	rom[sgbFlagAddr] = 0x03
+	rom[oldLicenseeAddr] = useNewLicenseeMarker
+	copy(rom[newLicenseeAddr:], "01")
+
+	h, err := Parse(rom)
+
+	assert.Nil(t, err)
+	assert.Equal(t, "POKEMON_GLD", h.Title)
+	assert.Equal(t, "AAUE", h.ManufacturerCode)
+	assert.Equal(t, CgbEnhanced, h.CgbSupport())
+	assert.True(t, h.SupportsSgb())
+	assert.Equal(t, "01", h.LicenseeCode())
+	assert.Equal(t, "Nintendo R&D1", h.Licensee())
+}
+
+func TestBadChecksums(t *testing.T) {
+	rom := withChecksums(testRom())
+	rom[versionAddr] = 0x03
+	rom[0x4000] = 0x01
+
+	h, _ := Parse(rom)
+
+	assert.False(t, h.IsHeaderChecksumValid())
+	assert.False(t, h.IsGlobalChecksumValid())
+}
+
+func TestParseShortRom(t *testing.T) {
+	_, err := Parse(make([]byte, 0x0100))
+	assert.Equal(t, ErrRomTooShort, err)
+}
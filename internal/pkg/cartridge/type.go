@@ -0,0 +1,95 @@
+package cartridge
+
+import "fmt"
+
+type MapperKind int
+
+const (
+	NoMapper MapperKind = iota
+	Mbc1
+	Mbc2
+	Mbc3
+	Mbc5
+	UnsupportedMapper
+)
+
+func (k MapperKind) String() string {
+	return []string{
+		"None", "MBC1", "MBC2", "MBC3", "MBC5", "Unsupported",
+	}[k]
+}
+
+// Type is the cartridge type byte at 0x0147 - it names the mapper and any
+// extra hardware on the cartridge.
+type Type byte
+
+type typeInfo struct {
+	name    string
+	mapper  MapperKind
+	ram     bool
+	battery bool
+	timer   bool
+	rumble  bool
+}
+
+var types = map[Type]typeInfo{
+	0x00: {"ROM ONLY", NoMapper, false, false, false, false},
+	0x01: {"MBC1", Mbc1, false, false, false, false},
+	0x02: {"MBC1+RAM", Mbc1, true, false, false, false},
+	0x03: {"MBC1+RAM+BATTERY", Mbc1, true, true, false, false},
+	0x05: {"MBC2", Mbc2, true, false, false, false},
+	0x06: {"MBC2+BATTERY", Mbc2, true, true, false, false},
+	0x08: {"ROM+RAM", NoMapper, true, false, false, false},
+	0x09: {"ROM+RAM+BATTERY", NoMapper, true, true, false, false},
+	0x0B: {"MMM01", UnsupportedMapper, false, false, false, false},
+	0x0C: {"MMM01+RAM", UnsupportedMapper, true, false, false, false},
+	0x0D: {"MMM01+RAM+BATTERY", UnsupportedMapper, true, true, false, false},
+	0x0F: {"MBC3+TIMER+BATTERY", Mbc3, false, true, true, false},
+	0x10: {"MBC3+TIMER+RAM+BATTERY", Mbc3, true, true, true, false},
+	0x11: {"MBC3", Mbc3, false, false, false, false},
+	0x12: {"MBC3+RAM", Mbc3, true, false, false, false},
+	0x13: {"MBC3+RAM+BATTERY", Mbc3, true, true, false, false},
+	0x19: {"MBC5", Mbc5, false, false, false, false},
+	0x1A: {"MBC5+RAM", Mbc5, true, false, false, false},
+	0x1B: {"MBC5+RAM+BATTERY", Mbc5, true, true, false, false},
+	0x1C: {"MBC5+RUMBLE", Mbc5, false, false, false, true},
+	0x1D: {"MBC5+RUMBLE+RAM", Mbc5, true, false, false, true},
+	0x1E: {"MBC5+RUMBLE+RAM+BATTERY", Mbc5, true, true, false, true},
+	0x20: {"MBC6", UnsupportedMapper, true, true, false, false},
+	0x22: {"MBC7+SENSOR+RUMBLE+RAM+BATTERY", UnsupportedMapper, true, true, false, true},
+	0xFC: {"POCKET CAMERA", UnsupportedMapper, true, true, false, false},
+	0xFD: {"BANDAI TAMA5", UnsupportedMapper, true, true, false, false},
+	0xFE: {"HuC3", UnsupportedMapper, true, true, true, false},
+	0xFF: {"HuC1+RAM+BATTERY", UnsupportedMapper, true, true, false, false},
+}
+
+func (t Type) info() typeInfo {
+	if info, ok := types[t]; ok {
+		return info
+	}
+	return typeInfo{name: fmt.Sprintf("UNKNOWN (0x%02X)", byte(t)), mapper: UnsupportedMapper}
+}
+
+func (t Type) String() string {
+	return t.info().name
+}
+
+func (t Type) Mapper() MapperKind {
+	return t.info().mapper
+}
+
+func (t Type) HasRam() bool {
+	return t.info().ram
+}
+
+func (t Type) HasBattery() bool {
+	return t.info().battery
+}
+
+func (t Type) HasTimer() bool {
+	return t.info().timer
+}
+
+func (t Type) HasRumble() bool {
+	return t.info().rumble
+}
@@ -0,0 +1,156 @@
+package cheats
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+type Kind int
+
+const (
+	GameGenie Kind = iota
+	GameShark
+)
+
+func (k Kind) String() string {
+	switch k {
+	case GameGenie:
+		return "Game Genie"
+	case GameShark:
+		return "GameShark"
+	}
+	return fmt.Sprintf("Kind(%d)", int(k))
+}
+
+/*
+	Game Genie - ABC-DEF or ABC-DEF-GHI, replacing a byte as it's read from rom
+
+	AB   - the new value
+	FCDE - the address, with F inverted
+	GI   - (optional) the byte to replace, rotated left by 2 and xor'd with
+		0xBA. If given, the code only applies when the rom holds this value,
+		so the right bank is patched in banked roms. H is unused.
+
+	The address has to be in rom, 0x0000-0x7FFF.
+
+	GameShark - ABCDEFGH, writing a byte to ram every frame
+
+	AB   - ram bank (0x01 for the usual, unbanked, ram). Codes for other
+		banks are rejected, as they'd need writing only while that bank is
+		mapped.
+	CD   - the value
+	GHEF - the address, which has to be in cartridge or work ram
+		(0xA000-0xDFFF) - writing anywhere else every frame would hit the
+		mapper or hardware registers
+*/
+
+// unbankedRam is the GameShark bank for the usual ram, whatever's mapped
+const unbankedRam = 0x01
+
+const (
+	romEnd   = 0x7FFF
+	ramStart = 0xA000
+	ramEnd   = 0xDFFF
+)
+
+type Cheat struct {
+	Code       string
+	Name       string
+	Kind       Kind
+	Enabled    bool
+	Address    uint16
+	Value      byte
+	Compare    byte
+	HasCompare bool
+	Bank       byte
+}
+
+// Parse decodes a Game Genie or GameShark code. Cheats start enabled.
+func Parse(code string) (Cheat, error) {
+	code = normalize(code)
+	digits := strings.ReplaceAll(code, "-", "")
+	b, err := hex.DecodeString(evenLength(digits))
+	if err != nil {
+		return Cheat{}, fmt.Errorf("invalid cheat code %q: not hex", code)
+	}
+
+	switch {
+	case len(digits) == 8 && !strings.Contains(code, "-"):
+		c := Cheat{
+			Code:    code,
+			Kind:    GameShark,
+			Enabled: true,
+			Bank:    b[0],
+			Value:   b[1],
+			Address: uint16(b[3])<<8 | uint16(b[2]),
+		}
+		if c.Address < ramStart || c.Address > ramEnd {
+			return Cheat{}, fmt.Errorf("invalid cheat code %q: address %04X isn't in ram (A000-DFFF)", code, c.Address)
+		}
+		return c, nil
+	case len(digits) == 6 || len(digits) == 9:
+		c := parseGameGenie(code, digits)
+		if c.Address > romEnd {
+			return Cheat{}, fmt.Errorf("invalid cheat code %q: address %04X isn't in rom (0000-7FFF)", code, c.Address)
+		}
+		return c, nil
+	default:
+		return Cheat{}, fmt.Errorf("invalid cheat code %q: expected ABC-DEF, ABC-DEF-GHI or ABCDEFGH", code)
+	}
+}
+
+func parseGameGenie(code string, digits string) Cheat {
+	d := func(i int) uint16 {
+		v, _ := strconv.ParseUint(digits[i:i+1], 16, 8)
+		return uint16(v)
+	}
+
+	c := Cheat{
+		Code:    code,
+		Kind:    GameGenie,
+		Enabled: true,
+		Value:   byte(d(0)<<4 | d(1)),
+		Address: (d(5)^0xF)<<12 | d(2)<<8 | d(3)<<4 | d(4),
+	}
+	if len(digits) == 9 {
+		compare := byte(d(6)<<4 | d(8))
+		c.Compare = (compare>>2 | compare<<6) ^ 0xBA
+		c.HasCompare = true
+	}
+	return c
+}
+
+// normalize upper cases a code and adds the dashes to Game Genie codes, so
+// the same code is always written the same way.
+func normalize(code string) string {
+	code = strings.ToUpper(strings.Join(strings.Fields(code), ""))
+	digits := strings.ReplaceAll(code, "-", "")
+	if strings.Contains(code, "-") || len(digits) == 6 || len(digits) == 9 {
+		groups := make([]string, 0, 3)
+		for i := 0; i < len(digits); i += 3 {
+			end := i + 3
+			if end > len(digits) {
+				end = len(digits)
+			}
+			groups = append(groups, digits[i:end])
+		}
+		return strings.Join(groups, "-")
+	}
+	return code
+}
+
+func evenLength(digits string) string {
+	if len(digits)%2 == 1 {
+		return digits + "0"
+	}
+	return digits
+}
+
+func (c Cheat) String() string {
+	if c.Name == "" {
+		return c.Code
+	}
+	return fmt.Sprintf("%s (%s)", c.Code, c.Name)
+}
@@ -0,0 +1,118 @@
+package cheats
+
+import (
+	"bytes"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestParseGameGenie(t *testing.T) {
+	c, err := Parse("3e1-5af-e6a")
+	assert.Nil(t, err)
+	assert.Equal(t, "3E1-5AF-E6A", c.Code)
+	assert.Equal(t, GameGenie, c.Kind)
+	assert.Equal(t, uint8(0x3E), c.Value)
+	assert.Equal(t, uint16(0x015A), c.Address)
+	assert.True(t, c.HasCompare)
+	assert.Equal(t, uint8(0x00), c.Compare)
+
+	c, err = Parse("01A 0BC")
+	assert.Nil(t, err)
+	assert.Equal(t, "01A-0BC", c.Code)
+	assert.Equal(t, uint16(0x3A0B), c.Address)
+	assert.False(t, c.HasCompare)
+}
+
+func TestParseGameShark(t *testing.T) {
+	c, err := Parse("01ff0ac1")
+	assert.Nil(t, err)
+	assert.Equal(t, GameShark, c.Kind)
+	assert.Equal(t, uint8(0x01), c.Bank)
+	assert.Equal(t, uint8(0xFF), c.Value)
+	assert.Equal(t, uint16(0xC10A), c.Address)
+}
+
+func TestParseInvalid(t *testing.T) {
+	// as well as bad formats, GameShark codes outside ram and Game Genie codes
+	// outside rom
+	for _, code := range []string{"", "01FF0AC", "XYZ-123", "01FF-0AC1", "01FF0020", "01FF80FF", "01FF00E0", "3E1-5A7-E6A"} {
+		_, err := Parse(code)
+		assert.NotNil(t, err, code)
+	}
+}
+
+func TestPatchRomRead(t *testing.T) {
+	e := NewEngine()
+	assert.False(t, e.HasRomCheats())
+
+	// compare value 0x00
+	_, err := e.Add("3E1-5AF-E6A", "")
+	assert.Nil(t, err)
+	assert.True(t, e.HasRomCheats())
+	assert.Equal(t, uint8(0x3E), e.PatchRomRead(0x015A, 0x00))
+	assert.Equal(t, uint8(0x12), e.PatchRomRead(0x015A, 0x12))
+	assert.Equal(t, uint8(0x00), e.PatchRomRead(0x015B, 0x00))
+
+	assert.Nil(t, e.SetEnabled("3e1-5af-e6a", false))
+	assert.False(t, e.HasRomCheats())
+	assert.Equal(t, uint8(0x00), e.PatchRomRead(0x015A, 0x00))
+}
+
+type fakeRam map[uint16]byte
+
+func (r fakeRam) WriteAddr(addr uint16, value byte) {
+	r[addr] = value
+}
+
+func TestApplyRamWrites(t *testing.T) {
+	e := NewEngine()
+	e.Add("01FF0AC1", "")
+	e.Add("01050BC1", "")
+	e.Add("3E1-5AF-E6A", "")
+	e.SetEnabled("01050BC1", false)
+
+	ram := fakeRam{}
+	e.ApplyRamWrites(ram)
+	assert.Equal(t, fakeRam{0xC10A: 0xFF}, ram)
+}
+
+func TestAddRemove(t *testing.T) {
+	e := NewEngine()
+	_, err := e.Add("01FF0AC1", "Lives")
+	assert.Nil(t, err)
+	_, err = e.Add("01ff0ac1", "Again")
+	assert.NotNil(t, err)
+
+	assert.Nil(t, e.Remove("01FF0AC1"))
+	assert.Empty(t, e.Cheats())
+	assert.NotNil(t, e.Remove("01FF0AC1"))
+}
+
+func TestAddRejectsBankedGameShark(t *testing.T) {
+	e := NewEngine()
+	_, err := e.Add("91FF0AD1", "")
+	assert.NotNil(t, err)
+	assert.Empty(t, e.Cheats())
+
+	// they still parse, so the bank can be shown
+	c, err := Parse("91FF0AD1")
+	assert.Nil(t, err)
+	assert.Equal(t, uint8(0x91), c.Bank)
+}
+
+func TestCheatFileRoundTrip(t *testing.T) {
+	e := NewEngine()
+	assert.Nil(t, e.Read(bytes.NewBufferString("# my cheats\n\non 01FF0AC1 Infinite lives\noff 3e1-5af-e6a\n")))
+
+	cheats := e.Cheats()
+	assert.Equal(t, 2, len(cheats))
+	assert.Equal(t, "Infinite lives", cheats[0].Name)
+	assert.True(t, cheats[0].Enabled)
+	assert.False(t, cheats[1].Enabled)
+
+	out := new(bytes.Buffer)
+	assert.Nil(t, e.Write(out))
+	assert.Equal(t, "on 01FF0AC1 Infinite lives\noff 3E1-5AF-E6A\n", out.String())
+
+	assert.NotNil(t, NewEngine().Read(bytes.NewBufferString("maybe 01FF0AC1\n")))
+}
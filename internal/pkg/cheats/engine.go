@@ -0,0 +1,172 @@
+package cheats
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// RamWriter is where GameShark codes write to - usually a memory.Controller.
+type RamWriter interface {
+	WriteAddr(addr uint16, value byte)
+}
+
+// Engine holds the cheats for a rom. Game Genie codes are applied through
+// PatchRomRead as the rom is read, GameShark codes through ApplyRamWrites
+// once a frame.
+type Engine struct {
+	cheats []*Cheat
+	genie  map[uint16][]*Cheat
+}
+
+func NewEngine() *Engine {
+	return &Engine{
+		genie: make(map[uint16][]*Cheat),
+	}
+}
+
+// Add decodes and adds a cheat, enabled.
+func (e *Engine) Add(code string, name string) (Cheat, error) {
+	c, err := Parse(code)
+	if err != nil {
+		return Cheat{}, err
+	}
+	if e.find(c.Code) != nil {
+		return Cheat{}, fmt.Errorf("cheat %s has already been added", c.Code)
+	}
+	if c.Kind == GameShark && c.Bank != unbankedRam {
+		return Cheat{}, fmt.Errorf("cheat %s writes to ram bank %02X, only bank 01 is supported", c.Code, c.Bank)
+	}
+	c.Name = name
+	e.cheats = append(e.cheats, &c)
+	e.rebuild()
+	return c, nil
+}
+
+func (e *Engine) Remove(code string) error {
+	code = normalize(code)
+	for i, c := range e.cheats {
+		if c.Code == code {
+			e.cheats = append(e.cheats[:i], e.cheats[i+1:]...)
+			e.rebuild()
+			return nil
+		}
+	}
+	return fmt.Errorf("no cheat %s", code)
+}
+
+func (e *Engine) SetEnabled(code string, enabled bool) error {
+	c := e.find(normalize(code))
+	if c == nil {
+		return fmt.Errorf("no cheat %s", normalize(code))
+	}
+	c.Enabled = enabled
+	e.rebuild()
+	return nil
+}
+
+// Cheats lists the cheats, in the order they were added.
+func (e *Engine) Cheats() []Cheat {
+	result := make([]Cheat, len(e.cheats))
+	for i, c := range e.cheats {
+		result[i] = *c
+	}
+	return result
+}
+
+func (e *Engine) find(code string) *Cheat {
+	for _, c := range e.cheats {
+		if c.Code == code {
+			return c
+		}
+	}
+	return nil
+}
+
+// rebuild indexes the enabled Game Genie codes by address, as rom reads
+// need to be fast.
+func (e *Engine) rebuild() {
+	e.genie = make(map[uint16][]*Cheat)
+	for _, c := range e.cheats {
+		if c.Enabled && c.Kind == GameGenie {
+			e.genie[c.Address] = append(e.genie[c.Address], c)
+		}
+	}
+}
+
+// HasRomCheats reports whether any Game Genie codes are enabled.
+func (e *Engine) HasRomCheats() bool {
+	return len(e.genie) > 0
+}
+
+// PatchRomRead gives the value to return for a rom read of addr, given the
+// value in the rom.
+func (e *Engine) PatchRomRead(addr uint16, value byte) byte {
+	for _, c := range e.genie[addr] {
+		if !c.HasCompare || c.Compare == value {
+			return c.Value
+		}
+	}
+	return value
+}
+
+// ApplyRamWrites writes the values of the enabled GameShark codes. Add only
+// takes codes for the usual ram, so they're written whatever bank is mapped.
+func (e *Engine) ApplyRamWrites(w RamWriter) {
+	for _, c := range e.cheats {
+		if c.Enabled && c.Kind == GameShark {
+			w.WriteAddr(c.Address, c.Value)
+		}
+	}
+}
+
+/*
+	cheat files - one cheat per line:
+
+		on 01FF0AC1 Infinite lives
+		off 3E1-5AF-E6A Level select
+
+	Blank lines and lines starting with # are ignored.
+*/
+
+// Read adds the cheats in a cheat file.
+func (e *Engine) Read(r io.Reader) error {
+	s := bufio.NewScanner(r)
+	lineNumber := 0
+	for s.Scan() {
+		lineNumber += 1
+		line := strings.TrimSpace(s.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		fields := strings.Fields(line)
+		if len(fields) < 2 || (fields[0] != "on" && fields[0] != "off") {
+			return fmt.Errorf("line %d: expected on|off CODE [name]", lineNumber)
+		}
+		c, err := e.Add(fields[1], strings.Join(fields[2:], " "))
+		if err != nil {
+			return fmt.Errorf("line %d: %w", lineNumber, err)
+		}
+		if fields[0] == "off" {
+			e.SetEnabled(c.Code, false)
+		}
+	}
+	return s.Err()
+}
+
+// Write writes the cheats in the cheat file format.
+func (e *Engine) Write(w io.Writer) error {
+	for _, c := range e.cheats {
+		state := "on"
+		if !c.Enabled {
+			state = "off"
+		}
+		line := strings.TrimSpace(fmt.Sprintf("%s %s %s", state, c.Code, c.Name))
+		if _, err := fmt.Fprintln(w, line); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,158 @@
+package cheats
+
+import "fmt"
+
+/*
+	Cheat finder - narrows down where a game keeps a value (lives, score...)
+	by comparing successive snapshots of ram:
+
+	1. start a search, which takes a snapshot of every searchable address
+	2. play a bit, then filter - eg. "decreased" after losing a life, or
+	   "equal to 3" when you know the value
+	3. repeat until only a few addresses are left
+*/
+
+type Width int
+
+const (
+	Width8  Width = 1
+	Width16 Width = 2
+)
+
+type Comparison int
+
+const (
+	Equal Comparison = iota
+	Changed
+	Unchanged
+	Increased
+	Decreased
+)
+
+var comparisonNames = map[string]Comparison{
+	"equal":     Equal,
+	"changed":   Changed,
+	"unchanged": Unchanged,
+	"increased": Increased,
+	"decreased": Decreased,
+}
+
+func ParseComparison(name string) (Comparison, error) {
+	if c, ok := comparisonNames[name]; ok {
+		return c, nil
+	}
+	return 0, fmt.Errorf("unknown comparison %q", name)
+}
+
+func (c Comparison) String() string {
+	for name, v := range comparisonNames {
+		if v == c {
+			return name
+		}
+	}
+	return fmt.Sprintf("Comparison(%d)", int(c))
+}
+
+type Region struct {
+	Name  string
+	Start uint16
+	End   uint16
+}
+
+// SearchRegions are the parts of memory a game keeps its variables in.
+var SearchRegions = []Region{
+	{"cartridge ram", 0xA000, 0xBFFF},
+	{"work ram", 0xC000, 0xDFFF},
+	{"high ram", 0xFF80, 0xFFFE},
+}
+
+type SearchResult struct {
+	Address  uint16
+	Value    uint16
+	Previous uint16
+}
+
+// Search filters addresses across snapshots of memory, as returned by
+// memory.Controller's ReadAll. 16 bit values are little endian, and both
+// bytes have to be in the same region.
+type Search struct {
+	width      Width
+	previous   []byte
+	candidates []uint16
+}
+
+func NewSearch(snapshot []byte, width Width) *Search {
+	s := &Search{
+		width:    width,
+		previous: snapshot,
+	}
+	for _, r := range SearchRegions {
+		for addr := int(r.Start); addr+int(width)-1 <= int(r.End); addr++ {
+			s.candidates = append(s.candidates, uint16(addr))
+		}
+	}
+	return s
+}
+
+func (s *Search) Width() Width {
+	return s.width
+}
+
+func (s *Search) value(snapshot []byte, addr uint16) uint16 {
+	v := uint16(snapshot[addr])
+	if s.width == Width16 {
+		v |= uint16(snapshot[addr+1]) << 8
+	}
+	return v
+}
+
+// Filter keeps the addresses whose value in snapshot passes the comparison
+// with their value in the last snapshot (or, for Equal, with value). It
+// returns how many addresses are left.
+func (s *Search) Filter(snapshot []byte, comparison Comparison, value uint16) int {
+	kept := s.candidates[:0]
+	for _, addr := range s.candidates {
+		current, previous := s.value(snapshot, addr), s.value(s.previous, addr)
+		var keep bool
+		switch comparison {
+		case Equal:
+			keep = current == value
+		case Changed:
+			keep = current != previous
+		case Unchanged:
+			keep = current == previous
+		case Increased:
+			keep = current > previous
+		case Decreased:
+			keep = current < previous
+		}
+		if keep {
+			kept = append(kept, addr)
+		}
+	}
+	s.candidates = kept
+	s.previous = snapshot
+	return len(s.candidates)
+}
+
+func (s *Search) Count() int {
+	return len(s.candidates)
+}
+
+// Results gives up to limit of the remaining addresses (all of them if limit
+// is 0), with their values in the last two snapshots.
+func (s *Search) Results(snapshot []byte, limit int) []SearchResult {
+	n := len(s.candidates)
+	if limit > 0 && limit < n {
+		n = limit
+	}
+	results := make([]SearchResult, n)
+	for i, addr := range s.candidates[:n] {
+		results[i] = SearchResult{
+			Address:  addr,
+			Value:    s.value(snapshot, addr),
+			Previous: s.value(s.previous, addr),
+		}
+	}
+	return results
+}
@@ -0,0 +1,62 @@
+package cheats
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestSearch8Bit(t *testing.T) {
+	mem := make([]byte, 0xFFFF)
+	mem[0xC123] = 3
+	mem[0xFF90] = 3
+	mem[0x8000] = 3
+
+	s := NewSearch(mem, Width8)
+	assert.Equal(t, 0x2000+0x2000+0x7F, s.Count())
+
+	// vram isn't searched
+	assert.Equal(t, 2, s.Filter(clone(mem), Equal, 3))
+
+	next := clone(mem)
+	next[0xC123] = 2
+	assert.Equal(t, 1, s.Filter(next, Decreased, 0))
+	assert.Equal(t, []SearchResult{{0xC123, 2, 2}}, s.Results(next, 0))
+
+	after := clone(next)
+	after[0xC123] = 5
+	assert.Equal(t, []SearchResult{{0xC123, 5, 2}}, s.Results(after, 0))
+	assert.Equal(t, 1, s.Filter(after, Increased, 0))
+	assert.Equal(t, 0, s.Filter(after, Changed, 0))
+}
+
+func TestSearch16Bit(t *testing.T) {
+	mem := make([]byte, 0xFFFF)
+	mem[0xD000] = 0x34
+	mem[0xD001] = 0x12
+
+	s := NewSearch(mem, Width16)
+	// the last byte of each region can't start a 16 bit value
+	assert.Equal(t, 0x1FFF+0x1FFF+0x7E, s.Count())
+
+	assert.Equal(t, 1, s.Filter(clone(mem), Equal, 0x1234))
+	next := clone(mem)
+	next[0xD000] = 0x00
+	next[0xD001] = 0x13
+	assert.Equal(t, 1, s.Filter(next, Increased, 0))
+	assert.Equal(t, 1, s.Filter(clone(next), Unchanged, 0))
+	assert.Equal(t, uint16(0xD000), s.Results(next, 1)[0].Address)
+}
+
+func TestParseComparison(t *testing.T) {
+	c, err := ParseComparison("decreased")
+	assert.Nil(t, err)
+	assert.Equal(t, Decreased, c)
+	assert.Equal(t, "decreased", c.String())
+
+	_, err = ParseComparison("bigger")
+	assert.NotNil(t, err)
+}
+
+func clone(mem []byte) []byte {
+	return append([]byte{}, mem...)
+}
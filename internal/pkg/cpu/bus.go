@@ -0,0 +1,109 @@
+package cpu
+
+import "github.com/mr-tim/goboye/internal/pkg/memory"
+
+// Bus is what the processor reads and writes. memory.Controller is the real
+// thing, but anything that can be read and written will do - eg. a flat 64KiB
+// ram for running cpu test vectors.
+type Bus interface {
+	ReadAddr(addr uint16) byte
+	WriteAddr(addr uint16, value byte)
+}
+
+// Ticker is told about time passing inside an instruction. A processor with a
+// ticker is cycle accurate: it ticks the rest of the system before each memory
+// access, so the timer, display and interrupts see reads and writes at the
+// M-cycle they happen on hardware rather than at the end of the instruction.
+type Ticker interface {
+	Tick(cycles uint8)
+}
+
+// mCycle is the length of a single memory access, in clock cycles.
+const mCycle = 4
+
+func (p *processor) SetTicker(ticker Ticker) {
+	p.ticker = ticker
+}
+
+// tick advances the rest of the system by cycles, if the processor is cycle
+// accurate.
+func (p *processor) tick(cycles uint8) {
+	if p.ticker != nil && cycles > 0 {
+		p.ticker.Tick(cycles)
+		p.ticked += cycles
+	}
+}
+
+// tickRemaining ticks whatever an instruction's timing has left after its
+// memory accesses - the internal cycles spent on things like 16-bit
+// arithmetic or loading pc.
+func (p *processor) tickRemaining(cycles uint8) {
+	if p.ticked < cycles {
+		p.tick(cycles - p.ticked)
+	}
+	p.ticked = 0
+}
+
+func (p *processor) readAddr(addr uint16) byte {
+	p.tick(mCycle)
+	return p.bus.ReadAddr(addr)
+}
+
+func (p *processor) writeAddr(addr uint16, value byte) {
+	p.tick(mCycle)
+	p.bus.WriteAddr(addr, value)
+}
+
+func (p *processor) readAddrU16(addr uint16) uint16 {
+	l := p.readAddr(addr)
+	h := p.readAddr(addr + 1)
+	return uint16(h)<<8 | uint16(l)
+}
+
+func (p *processor) writeAddrU16(addr, value uint16) {
+	p.writeAddr(addr, byte(value))
+	p.writeAddr(addr+1, byte(value>>8))
+}
+
+// push16 pushes value onto the stack, high byte first as the hardware does.
+func (p *processor) push16(value uint16) {
+	p.registers.sp--
+	p.writeAddr(p.registers.sp, byte(value>>8))
+	p.registers.sp--
+	p.writeAddr(p.registers.sp, byte(value))
+}
+
+// The io registers the processor uses itself. Going straight to the memory
+// controller's registers is quicker, when there is one.
+
+func (p *processor) interruptRegisters() (enabled, flags byte) {
+	if p.memory == nil {
+		return p.bus.ReadAddr(memory.InterruptsEnabledAddress), p.bus.ReadAddr(memory.InterruptFlagsAddress)
+	}
+	return p.memory.InterruptEnabled.Read(), p.memory.InterruptFlags.Read()
+}
+
+func (p *processor) writeInterruptFlags(flags byte) {
+	if p.memory == nil {
+		p.bus.WriteAddr(memory.InterruptFlagsAddress, flags)
+		return
+	}
+	p.memory.InterruptFlags.Write(flags)
+}
+
+// isJoypadLineLow reports whether a selected joypad button is held, which
+// wakes the cpu from STOP.
+func (p *processor) isJoypadLineLow() bool {
+	if p.memory == nil {
+		return p.bus.ReadAddr(0xFF00)&0x0F != 0x0F
+	}
+	return p.memory.ControllerData.IsLineLow()
+}
+
+func (p *processor) resetDivider() {
+	if p.memory == nil {
+		p.bus.WriteAddr(memory.DividerAddress, 0x00)
+		return
+	}
+	p.memory.ResetDivider()
+}
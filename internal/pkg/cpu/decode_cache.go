@@ -0,0 +1,47 @@
+package cpu
+
+// romEnd is the first address past the cartridge rom.
+const romEnd = 0x8000
+
+// decodedInstruction is an opcode decoded from rom, and how many bytes of
+// opcode (2 for CB prefixed instructions) it was read from.
+type decodedInstruction struct {
+	op      *opcode
+	length  uint8
+	version uint32
+}
+
+// decodeCache keeps the opcode decoded at each rom address, so running code
+// from rom skips the memory reads and table lookups. Entries are tagged with
+// the memory controller's RomMapVersion, so a bank switch invalidates them
+// without having to clear anything.
+type decodeCache struct {
+	entries [romEnd]decodedInstruction
+}
+
+func (c *decodeCache) lookup(addr uint16, version uint32) (*opcode, uint8) {
+	if addr >= romEnd {
+		return nil, 0
+	}
+	e := &c.entries[addr]
+	if e.op == nil || e.version != version {
+		return nil, 0
+	}
+	return e.op, e.length
+}
+
+func (c *decodeCache) store(addr uint16, op *opcode, length uint8, version uint32) {
+	// an instruction running past the end of rom depends on ram
+	if int(addr)+int(length) > romEnd {
+		return
+	}
+	c.entries[addr] = decodedInstruction{op, length, version}
+}
+
+func (p *processor) SetDecodeCache(enabled bool) {
+	if !enabled {
+		p.decodeCache = nil
+	} else if p.decodeCache == nil {
+		p.decodeCache = &decodeCache{}
+	}
+}
@@ -42,7 +42,7 @@ func Disassemble(m *memory.Controller, pos uint16) (uint16, OpcodeAndPayload, ui
 	}
 
 	op := OpcodeAndPayload{
-		op:      &o,
+		op:      o,
 		payload: payload,
 	}
 
@@ -0,0 +1,30 @@
+package cpu
+
+import (
+	"github.com/mr-tim/goboye/internal/pkg/memory"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestDisassembleSignedImmediates(t *testing.T) {
+	m := memory.NewControllerWithBytes([]byte{
+		0xE8, 0xFE, // ADD SP,-2
+		0xF8, 0x05, // LDHL SP,5
+		0x00, // NOP
+	})
+	m.BootRomRegister.Write(0x01)
+	d := NewDisassembler(&m)
+
+	// the signed byte is part of each instruction, not the start of the next
+	addr, op := d.GetNextInstruction()
+	assert.Equal(t, uint16(0x0000), addr)
+	assert.Equal(t, "ADD SP,0xFE", op.Disassembly())
+
+	addr, op = d.GetNextInstruction()
+	assert.Equal(t, uint16(0x0002), addr)
+	assert.Equal(t, "LDHL SP,0x05", op.Disassembly())
+
+	addr, op = d.GetNextInstruction()
+	assert.Equal(t, uint16(0x0004), addr)
+	assert.Equal(t, "NOP", op.Disassembly())
+}
@@ -1,22 +1,15 @@
 package cpu
 
-func extendedOps(op opcode, p *processor) {
-	opCodeByte := p.memory.ReadAddr(p.registers.pc)
-	p.registers.pc++
-	extendedOpcode := LookupExtOpcode(opCodeByte)
-	extendedOpcode.handler(extendedOpcode, p)
-}
-
 func testBitOfReg(bit uint8, reg register) opcodeHandler {
-	return func(op opcode, p *processor) {
+	return func(op *opcode, p *processor) {
 		value := p.registers.getRegister(reg)
 		doTestBit(p, bit, value)
 	}
 }
 
 func testBitOfHLAddr(bit uint8) opcodeHandler {
-	return func(op opcode, p *processor) {
-		value := p.memory.ReadAddr(p.registers.hl)
+	return func(op *opcode, p *processor) {
+		value := p.readAddr(p.registers.hl)
 		doTestBit(p, bit, value)
 	}
 }
@@ -33,40 +26,40 @@ func doTestBit(p *processor, bit, value uint8) {
 
 func clearBitOfReg(bit uint8, reg register) opcodeHandler {
 	mask := uint8(0xFF) - uint8(0x01<<bit)
-	return func(op opcode, p *processor) {
+	return func(op *opcode, p *processor) {
 		p.registers.setRegister(reg, p.registers.getRegister(reg)&mask)
 	}
 }
 
 func clearBitOfHLAddr(bit uint8) opcodeHandler {
 	mask := uint8(0xFF) - uint8(0x01<<bit)
-	return func(op opcode, p *processor) {
-		p.memory.WriteAddr(p.registers.hl, p.memory.ReadAddr(p.registers.hl)&mask)
+	return func(op *opcode, p *processor) {
+		p.writeAddr(p.registers.hl, p.readAddr(p.registers.hl)&mask)
 	}
 }
 
 func setBitOfReg(bit uint8, reg register) opcodeHandler {
 	mask := uint8(0x01 << bit)
-	return func(op opcode, p *processor) {
+	return func(op *opcode, p *processor) {
 		p.registers.setRegister(reg, p.registers.getRegister(reg)|mask)
 	}
 }
 
 func setBitOfHLAddr(bit uint8) opcodeHandler {
 	mask := uint8(0x01 << bit)
-	return func(op opcode, p *processor) {
-		p.memory.WriteAddr(p.registers.hl, p.memory.ReadAddr(p.registers.hl)|mask)
+	return func(op *opcode, p *processor) {
+		p.writeAddr(p.registers.hl, p.readAddr(p.registers.hl)|mask)
 	}
 }
 
 func rotateRegLeftWithCarry(reg register) opcodeHandler {
-	return func(op opcode, p *processor) {
+	return func(op *opcode, p *processor) {
 		p.registers.setRegister(reg, doRotateLeft(p, p.registers.getRegister(reg), true))
 	}
 }
 
-func rotateHLAddrLeftWithCarry(op opcode, p *processor) {
-	p.memory.WriteAddr(p.registers.hl, doRotateLeft(p, p.memory.ReadAddr(p.registers.hl), true))
+func rotateHLAddrLeftWithCarry(op *opcode, p *processor) {
+	p.writeAddr(p.registers.hl, doRotateLeft(p, p.readAddr(p.registers.hl), true))
 }
 
 func doRotateLeft(p *processor, value uint8, carry bool) uint8 {
@@ -92,13 +85,13 @@ func setLeftShiftFlags(p *processor, result uint8, value uint8) {
 }
 
 func rotateRegRightWithCarry(reg register) opcodeHandler {
-	return func(op opcode, p *processor) {
+	return func(op *opcode, p *processor) {
 		p.registers.setRegister(reg, doRotateRight(p, p.registers.getRegister(reg), true))
 	}
 }
 
-func rotateHLAddrRightWithCarry(op opcode, p *processor) {
-	p.memory.WriteAddr(p.registers.hl, doRotateRight(p, p.memory.ReadAddr(p.registers.hl), true))
+func rotateHLAddrRightWithCarry(op *opcode, p *processor) {
+	p.writeAddr(p.registers.hl, doRotateRight(p, p.readAddr(p.registers.hl), true))
 }
 
 func doRotateRight(p *processor, value uint8, carry bool) uint8 {
@@ -125,35 +118,35 @@ func setRightShiftFlags(p *processor, result uint8, value uint8) {
 }
 
 func rotateRegLeft(reg register) opcodeHandler {
-	return func(op opcode, p *processor) {
+	return func(op *opcode, p *processor) {
 		p.registers.setRegister(reg, doRotateLeft(p, p.registers.getRegister(reg), false))
 	}
 }
 
-func rotateHLAddrLeft(op opcode, p *processor) {
-	p.memory.WriteAddr(p.registers.hl, doRotateLeft(p, p.memory.ReadAddr(p.registers.hl), false))
+func rotateHLAddrLeft(op *opcode, p *processor) {
+	p.writeAddr(p.registers.hl, doRotateLeft(p, p.readAddr(p.registers.hl), false))
 }
 
 func rotateRegRight(reg register) opcodeHandler {
-	return func(op opcode, p *processor) {
+	return func(op *opcode, p *processor) {
 		p.registers.setRegister(reg, doRotateRight(p, p.registers.getRegister(reg), false))
 	}
 }
 
-func rotateHLAddrRight(op opcode, p *processor) {
-	p.memory.WriteAddr(p.registers.hl, doRotateRight(p, p.memory.ReadAddr(p.registers.hl), false))
+func rotateHLAddrRight(op *opcode, p *processor) {
+	p.writeAddr(p.registers.hl, doRotateRight(p, p.readAddr(p.registers.hl), false))
 }
 
 func shiftRegLeftPreservingSign(reg register) opcodeHandler {
-	return func(op opcode, p *processor) {
+	return func(op *opcode, p *processor) {
 		value := p.registers.getRegister(reg)
 		p.registers.setRegister(reg, doShiftLeftPreservingSign(p, value))
 	}
 }
 
-func shiftHLAddrLeftPreservingSign(op opcode, p *processor) {
-	value := p.memory.ReadAddr(p.registers.hl)
-	p.memory.WriteAddr(p.registers.hl, doShiftLeftPreservingSign(p, value))
+func shiftHLAddrLeftPreservingSign(op *opcode, p *processor) {
+	value := p.readAddr(p.registers.hl)
+	p.writeAddr(p.registers.hl, doShiftLeftPreservingSign(p, value))
 }
 
 func doShiftLeftPreservingSign(p *processor, value uint8) uint8 {
@@ -163,15 +156,15 @@ func doShiftLeftPreservingSign(p *processor, value uint8) uint8 {
 }
 
 func shiftRegRightPreservingSign(reg register) opcodeHandler {
-	return func(op opcode, p *processor) {
+	return func(op *opcode, p *processor) {
 		value := p.registers.getRegister(reg)
 		p.registers.setRegister(reg, doShiftRightPreservingSign(p, value))
 	}
 }
 
-func shiftHLAddrRightPreservingSign(op opcode, p *processor) {
-	value := p.memory.ReadAddr(p.registers.hl)
-	p.memory.WriteAddr(p.registers.hl, doShiftRightPreservingSign(p, value))
+func shiftHLAddrRightPreservingSign(op *opcode, p *processor) {
+	value := p.readAddr(p.registers.hl)
+	p.writeAddr(p.registers.hl, doShiftRightPreservingSign(p, value))
 }
 
 func doShiftRightPreservingSign(p *processor, value uint8) uint8 {
@@ -181,15 +174,15 @@ func doShiftRightPreservingSign(p *processor, value uint8) uint8 {
 }
 
 func shiftRegRight(reg register) opcodeHandler {
-	return func(op opcode, p *processor) {
+	return func(op *opcode, p *processor) {
 		value := p.registers.getRegister(reg)
 		p.registers.setRegister(reg, doShiftRight(p, value))
 	}
 }
 
-func shiftHLAddrRight(op opcode, p *processor) {
-	value := p.memory.ReadAddr(p.registers.hl)
-	p.memory.WriteAddr(p.registers.hl, doShiftRight(p, value))
+func shiftHLAddrRight(op *opcode, p *processor) {
+	value := p.readAddr(p.registers.hl)
+	p.writeAddr(p.registers.hl, doShiftRight(p, value))
 }
 
 func doShiftRight(p *processor, value uint8) uint8 {
@@ -199,15 +192,15 @@ func doShiftRight(p *processor, value uint8) uint8 {
 }
 
 func swapRegNybbles(reg register) opcodeHandler {
-	return func(op opcode, p *processor) {
+	return func(op *opcode, p *processor) {
 		value := p.registers.getRegister(reg)
 		p.registers.setRegister(reg, doSwapNybbles(p, value))
 	}
 }
 
-func swapHLAddrNybbles(op opcode, p *processor) {
-	value := p.memory.ReadAddr(p.registers.hl)
-	p.memory.WriteAddr(p.registers.hl, doSwapNybbles(p, value))
+func swapHLAddrNybbles(op *opcode, p *processor) {
+	value := p.readAddr(p.registers.hl)
+	p.writeAddr(p.registers.hl, doSwapNybbles(p, value))
 }
 
 func doSwapNybbles(p *processor, value uint8) uint8 {
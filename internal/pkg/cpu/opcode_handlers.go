@@ -1,15 +1,15 @@
 package cpu
 
-import "fmt"
-
-func unsupportedHandler(op opcode, p *processor) {
-	panic(fmt.Sprintf("Unsupported opcode: %#v\n", op))
+// illegalOpcode hangs the cpu, as the undefined opcodes do on hardware. Only
+// a reset gets it going again.
+func illegalOpcode(op *opcode, p *processor) {
+	p.lockup = &IllegalOpcode{Address: p.registers.pc - 1, Opcode: op.code}
 }
 
-func nopHandler(op opcode, p *processor) {}
+func nopHandler(op *opcode, p *processor) {}
 
 func load16BitToRegPair(rp RegisterPair) opcodeHandler {
-	return func(op opcode, p *processor) {
+	return func(op *opcode, p *processor) {
 		doLoad16BitToRegPair(p, rp)
 	}
 }
@@ -20,7 +20,7 @@ func doLoad16BitToRegPair(p *processor, pair RegisterPair) {
 }
 
 func load8BitToReg(r register) opcodeHandler {
-	return func(op opcode, p *processor) {
+	return func(op *opcode, p *processor) {
 		doLoad8BitToReg(p, r)
 	}
 }
@@ -30,62 +30,62 @@ func doLoad8BitToReg(p *processor, reg register) {
 	p.registers.setRegister(reg, value)
 }
 
-func load8BitToHLAddr(op opcode, p *processor) {
+func load8BitToHLAddr(op *opcode, p *processor) {
 	value := p.Read8BitImmediate()
-	p.memory.WriteAddr(p.registers.hl, value)
+	p.writeAddr(p.registers.hl, value)
 }
 
 func loadRegToReg(to, from register) opcodeHandler {
-	return func(op opcode, p *processor) {
+	return func(op *opcode, p *processor) {
 		p.registers.setRegister(to, p.registers.getRegister(from))
 	}
 }
 
 func loadHLAddrToReg(to register) opcodeHandler {
-	return func(op opcode, p *processor) {
-		value := p.memory.ReadAddr(p.registers.hl)
+	return func(op *opcode, p *processor) {
+		value := p.readAddr(p.registers.hl)
 		p.registers.setRegister(to, value)
 	}
 }
 
 func loadRegToHLAddr(from register) opcodeHandler {
-	return func(op opcode, p *processor) {
-		p.memory.WriteAddr(p.registers.hl, p.registers.getRegister(from))
+	return func(op *opcode, p *processor) {
+		p.writeAddr(p.registers.hl, p.registers.getRegister(from))
 	}
 }
 
-func saveAToBCAddr(op opcode, p *processor) {
-	p.memory.WriteAddr(p.registers.bc, p.registers.getRegister(RegisterA))
+func saveAToBCAddr(op *opcode, p *processor) {
+	p.writeAddr(p.registers.bc, p.registers.getRegister(RegisterA))
 }
 
-func saveAToDEAddr(op opcode, p *processor) {
-	p.memory.WriteAddr(p.registers.de, p.registers.getRegister(RegisterA))
+func saveAToDEAddr(op *opcode, p *processor) {
+	p.writeAddr(p.registers.de, p.registers.getRegister(RegisterA))
 }
 
-func saveAToHLAddrInc(op opcode, p *processor) {
-	p.memory.WriteAddr(p.registers.hl, p.registers.getRegister(RegisterA))
+func saveAToHLAddrInc(op *opcode, p *processor) {
+	p.writeAddr(p.registers.hl, p.registers.getRegister(RegisterA))
 	p.registers.hl++
 }
 
-func saveAToHLAddrDec(op opcode, p *processor) {
-	p.memory.WriteAddr(p.registers.hl, p.registers.getRegister(RegisterA))
+func saveAToHLAddrDec(op *opcode, p *processor) {
+	p.writeAddr(p.registers.hl, p.registers.getRegister(RegisterA))
 	p.registers.hl--
 }
 
-func saveSPToAddr(op opcode, p *processor) {
+func saveSPToAddr(op *opcode, p *processor) {
 	addr := p.Read16BitImmediate()
 	sp := p.registers.sp
-	p.memory.WriteAddrU16(addr, sp)
+	p.writeAddrU16(addr, sp)
 }
 
 func incrementRegPair(pair RegisterPair) opcodeHandler {
-	return func(op opcode, p *processor) {
+	return func(op *opcode, p *processor) {
 		doIncrementRegPair(p, pair)
 	}
 }
 
 func decrementRegPair(pair RegisterPair) opcodeHandler {
-	return func(op opcode, p *processor) {
+	return func(op *opcode, p *processor) {
 		doDecrementRegPair(p, pair)
 	}
 }
@@ -98,28 +98,28 @@ func doDecrementRegPair(p *processor, rp RegisterPair) {
 	p.registers.setRegisterPair(rp, p.registers.getRegisterPair(rp)-1)
 }
 
-func incrementHLAddr(op opcode, p *processor) {
-	originalValue := p.memory.ReadAddr(p.registers.hl)
+func incrementHLAddr(op *opcode, p *processor) {
+	originalValue := p.readAddr(p.registers.hl)
 	newValue, flags := add(originalValue, 1, false)
-	p.memory.WriteAddr(p.registers.hl, newValue)
+	p.writeAddr(p.registers.hl, newValue)
 	p.registers.setFlags(updateIncDecFlags(p, flags))
 }
 
-func decrementHLAddr(op opcode, p *processor) {
-	originalValue := p.memory.ReadAddr(p.registers.hl)
+func decrementHLAddr(op *opcode, p *processor) {
+	originalValue := p.readAddr(p.registers.hl)
 	newValue, flags := subtract(originalValue, 1, false)
-	p.memory.WriteAddr(p.registers.hl, newValue)
+	p.writeAddr(p.registers.hl, newValue)
 	p.registers.setFlags(updateIncDecFlags(p, flags))
 }
 
 func incrementReg(reg register) opcodeHandler {
-	return func(op opcode, p *processor) {
+	return func(op *opcode, p *processor) {
 		doIncrementRegister(p, reg)
 	}
 }
 
 func decrementReg(reg register) opcodeHandler {
-	return func(op opcode, p *processor) {
+	return func(op *opcode, p *processor) {
 		doDecrementRegister(p, reg)
 	}
 }
@@ -160,26 +160,26 @@ func isHalfCarrySubtract(old, subtractValue uint8, carry bool) bool {
 }
 
 func addRegToA(reg register) opcodeHandler {
-	return func(op opcode, p *processor) {
+	return func(op *opcode, p *processor) {
 		toAdd := p.registers.getRegister(reg)
 		doAddValueToA(p, toAdd, false)
 	}
 }
 
-func addHLAddrToA(op opcode, p *processor) {
-	toAdd := p.memory.ReadAddr(p.registers.hl)
+func addHLAddrToA(op *opcode, p *processor) {
+	toAdd := p.readAddr(p.registers.hl)
 	doAddValueToA(p, toAdd, false)
 }
 
 func addRegAndCarryToA(reg register) opcodeHandler {
-	return func(op opcode, p *processor) {
+	return func(op *opcode, p *processor) {
 		toAdd := p.registers.getRegister(reg)
 		doAddValueToA(p, toAdd, p.registers.getFlagValue(FlagC))
 	}
 }
 
-func addHLAddrAndCarryToA(op opcode, p *processor) {
-	toAdd := p.memory.ReadAddr(p.registers.hl)
+func addHLAddrAndCarryToA(op *opcode, p *processor) {
+	toAdd := p.readAddr(p.registers.hl)
 	doAddValueToA(p, toAdd, p.registers.getFlagValue(FlagC))
 }
 
@@ -191,26 +191,26 @@ func doAddValueToA(p *processor, toAdd uint8, carry bool) {
 }
 
 func subtractRegFromA(reg register) opcodeHandler {
-	return func(op opcode, p *processor) {
+	return func(op *opcode, p *processor) {
 		toSubtract := p.registers.getRegister(reg)
 		doSubtractValueFromA(p, toSubtract, false)
 	}
 }
 
-func subtractHLAddrFromA(op opcode, p *processor) {
-	toSubtract := p.memory.ReadAddr(p.registers.hl)
+func subtractHLAddrFromA(op *opcode, p *processor) {
+	toSubtract := p.readAddr(p.registers.hl)
 	doSubtractValueFromA(p, toSubtract, false)
 }
 
 func subtractRegAndCarryFromA(reg register) opcodeHandler {
-	return func(op opcode, p *processor) {
+	return func(op *opcode, p *processor) {
 		toSubtract := p.registers.getRegister(reg)
 		doSubtractValueFromA(p, toSubtract, p.registers.getFlagValue(FlagC))
 	}
 }
 
-func subtractHLAddrAndCarryFromA(op opcode, p *processor) {
-	toSubtract := p.memory.ReadAddr(p.registers.hl)
+func subtractHLAddrAndCarryFromA(op *opcode, p *processor) {
+	toSubtract := p.readAddr(p.registers.hl)
 	doSubtractValueFromA(p, toSubtract, p.registers.getFlagValue(FlagC))
 }
 
@@ -222,14 +222,14 @@ func doSubtractValueFromA(p *processor, toSubtract uint8, carry bool) {
 }
 
 func logicalAndRegAgainstA(reg register) opcodeHandler {
-	return func(op opcode, p *processor) {
+	return func(op *opcode, p *processor) {
 		other := p.registers.getRegister(reg)
 		doLogicalAndAgainstA(p, other)
 	}
 }
 
-func logicalAndHLAddrAgainstA(op opcode, p *processor) {
-	other := p.memory.ReadAddr(p.registers.hl)
+func logicalAndHLAddrAgainstA(op *opcode, p *processor) {
+	other := p.readAddr(p.registers.hl)
 	doLogicalAndAgainstA(p, other)
 }
 
@@ -240,14 +240,14 @@ func doLogicalAndAgainstA(p *processor, other uint8) {
 }
 
 func logicalXorRegAgainstA(reg register) opcodeHandler {
-	return func(op opcode, p *processor) {
+	return func(op *opcode, p *processor) {
 		other := p.registers.getRegister(reg)
 		doLogicalXorAgainstA(p, other)
 	}
 }
 
-func logicalXorHLAddrAgainstA(op opcode, p *processor) {
-	other := p.memory.ReadAddr(p.registers.hl)
+func logicalXorHLAddrAgainstA(op *opcode, p *processor) {
+	other := p.readAddr(p.registers.hl)
 	doLogicalXorAgainstA(p, other)
 }
 
@@ -257,14 +257,14 @@ func doLogicalXorAgainstA(p *processor, other uint8) {
 }
 
 func logicalOrRegAgainstA(reg register) opcodeHandler {
-	return func(op opcode, p *processor) {
+	return func(op *opcode, p *processor) {
 		other := p.registers.getRegister(reg)
 		doLogicalOrAgainstA(p, other)
 	}
 }
 
-func logicalOrHLAddrAgainstA(op opcode, p *processor) {
-	other := p.memory.ReadAddr(p.registers.hl)
+func logicalOrHLAddrAgainstA(op *opcode, p *processor) {
+	other := p.readAddr(p.registers.hl)
 	doLogicalOrAgainstA(p, other)
 }
 
@@ -297,14 +297,14 @@ func doLogicalOpAgainstA(p *processor, other uint8, op func(a, b uint8) uint8) O
 }
 
 func compareRegAgainstA(reg register) opcodeHandler {
-	return func(op opcode, p *processor) {
+	return func(op *opcode, p *processor) {
 		value := p.registers.getRegister(reg)
 		doCompareValueAgainstA(p, value)
 	}
 }
 
-func compareHLAddrAgainstA(op opcode, p *processor) {
-	value := p.memory.ReadAddr(p.registers.hl)
+func compareHLAddrAgainstA(op *opcode, p *processor) {
+	value := p.readAddr(p.registers.hl)
 	doCompareValueAgainstA(p, value)
 }
 
@@ -326,7 +326,7 @@ func doCompareValueAgainstA(p *processor, value uint8) {
 }
 
 func addRegPairToHL(rp RegisterPair) opcodeHandler {
-	return func(op opcode, p *processor) {
+	return func(op *opcode, p *processor) {
 		flags := p.registers.getFlags() & FlagZ
 		original := p.registers.hl
 		toAdd := p.registers.getRegisterPair(rp)
@@ -342,34 +342,34 @@ func addRegPairToHL(rp RegisterPair) opcodeHandler {
 }
 
 func loadAFromRegPairAddr(rp RegisterPair) opcodeHandler {
-	return func(op opcode, p *processor) {
-		p.registers.setRegister(RegisterA, p.memory.ReadAddr(p.registers.getRegisterPair(rp)))
+	return func(op *opcode, p *processor) {
+		p.registers.setRegister(RegisterA, p.readAddr(p.registers.getRegisterPair(rp)))
 	}
 }
 
-func loadAFromHLAddrInc(op opcode, p *processor) {
-	p.registers.setRegister(RegisterA, p.memory.ReadAddr(p.registers.hl))
+func loadAFromHLAddrInc(op *opcode, p *processor) {
+	p.registers.setRegister(RegisterA, p.readAddr(p.registers.hl))
 	p.registers.hl += 1
 }
 
-func loadAFromHLAddrDec(op opcode, p *processor) {
-	p.registers.setRegister(RegisterA, p.memory.ReadAddr(p.registers.hl))
+func loadAFromHLAddrDec(op *opcode, p *processor) {
+	p.registers.setRegister(RegisterA, p.readAddr(p.registers.hl))
 	p.registers.hl -= 1
 }
 
-func complementOnA(op opcode, p *processor) {
+func complementOnA(op *opcode, p *processor) {
 	flags := p.registers.getFlags() | FlagN | FlagH
 	p.registers.setRegister(RegisterA, ^p.registers.getRegister(RegisterA))
 	p.registers.setFlags(flags)
 }
 
-func setCarryFlag(op opcode, p *processor) {
+func setCarryFlag(op *opcode, p *processor) {
 	flags := p.registers.getFlags() & FlagZ
 	flags |= FlagC
 	p.registers.setFlags(flags)
 }
 
-func complementCarryFlag(op opcode, p *processor) {
+func complementCarryFlag(op *opcode, p *processor) {
 	flags := p.registers.getFlags() & FlagZ
 	if !p.registers.getFlagValue(FlagC) {
 		flags |= FlagC
@@ -377,7 +377,7 @@ func complementCarryFlag(op opcode, p *processor) {
 	p.registers.setFlags(flags)
 }
 
-func addImmediate(op opcode, p *processor) {
+func addImmediate(op *opcode, p *processor) {
 	original := p.registers.getRegister(RegisterA)
 	other := p.Read8BitImmediate()
 	result, flags := add(original, other, false)
@@ -385,7 +385,7 @@ func addImmediate(op opcode, p *processor) {
 	p.registers.setFlags(flags)
 }
 
-func subtractImmediate(op opcode, p *processor) {
+func subtractImmediate(op *opcode, p *processor) {
 	original := p.registers.getRegister(RegisterA)
 	other := p.Read8BitImmediate()
 	result, flags := subtract(original, other, false)
@@ -393,7 +393,7 @@ func subtractImmediate(op opcode, p *processor) {
 	p.registers.setFlags(flags)
 }
 
-func logicalAndImmediate(op opcode, p *processor) {
+func logicalAndImmediate(op *opcode, p *processor) {
 	other := p.Read8BitImmediate()
 	result := p.registers.getRegister(RegisterA) & other
 	p.registers.setRegister(RegisterA, result)
@@ -406,7 +406,7 @@ func logicalAndImmediate(op opcode, p *processor) {
 
 }
 
-func logicalOrImmediate(op opcode, p *processor) {
+func logicalOrImmediate(op *opcode, p *processor) {
 	other := p.Read8BitImmediate()
 	result := p.registers.getRegister(RegisterA) | other
 	p.registers.setRegister(RegisterA, result)
@@ -418,17 +418,17 @@ func logicalOrImmediate(op opcode, p *processor) {
 	p.registers.setFlags(flags)
 }
 
-func addCImmediate(op opcode, p *processor) {
+func addCImmediate(op *opcode, p *processor) {
 	other := p.Read8BitImmediate()
 	doAddValueToA(p, other, p.registers.getFlagValue(FlagC))
 }
 
-func subCImmediate(op opcode, p *processor) {
+func subCImmediate(op *opcode, p *processor) {
 	other := p.Read8BitImmediate()
 	doSubtractValueFromA(p, other, p.registers.getFlagValue(FlagC))
 }
 
-func logicalXorImmediate(op opcode, p *processor) {
+func logicalXorImmediate(op *opcode, p *processor) {
 	other := p.Read8BitImmediate()
 	result := p.registers.getRegister(RegisterA) ^ other
 	p.registers.setRegister(RegisterA, result)
@@ -440,12 +440,12 @@ func logicalXorImmediate(op opcode, p *processor) {
 	p.registers.setFlags(flags)
 }
 
-func compareImmediate(op opcode, p *processor) {
+func compareImmediate(op *opcode, p *processor) {
 	other := p.Read8BitImmediate()
 	doCompareValueAgainstA(p, other)
 }
 
-func relativeJumpImmediate(op opcode, p *processor) {
+func relativeJumpImmediate(op *opcode, p *processor) {
 	jumpValue := p.Read8BitImmediate()
 
 	doRelativeJump(jumpValue, p)
@@ -467,147 +467,141 @@ func doRelativeJump(unsignedJumpValue uint8, p *processor) {
 }
 
 func relativeJumpImmediateIfFlag(f OpResultFlag, value bool) opcodeHandler {
-	return func(op opcode, p *processor) {
+	return func(op *opcode, p *processor) {
 		jumpValue := p.Read8BitImmediate()
 
 		if p.registers.getFlagValue(f) == value {
-			p.cycles += 4
+			p.branchTaken = true
 			doRelativeJump(jumpValue, p)
 		}
 	}
 }
 
-func jumpToHLAddr(op opcode, p *processor) {
+func jumpToHLAddr(op *opcode, p *processor) {
 	p.registers.pc = p.registers.hl
 }
 
-func jumpTo16BitAddress(op opcode, p *processor) {
-	newAddr := p.memory.ReadAddrU16(p.registers.pc)
+func jumpTo16BitAddress(op *opcode, p *processor) {
+	newAddr := p.readAddrU16(p.registers.pc)
 	p.registers.pc = newAddr
 }
 
 func jumpTo16BitAddressIfFlag(f OpResultFlag, value bool) opcodeHandler {
-	return func(op opcode, p *processor) {
+	return func(op *opcode, p *processor) {
 		newAddr := p.Read16BitImmediate()
 
 		if p.registers.getFlagValue(f) == value {
-			p.cycles += 4
+			p.branchTaken = true
 			p.registers.pc = newAddr
 		}
 	}
 }
 
 func pushRegisterPair(rp RegisterPair) opcodeHandler {
-	return func(op opcode, p *processor) {
-		value := p.registers.getRegisterPair(rp)
-		p.registers.sp -= 2
-		p.memory.WriteAddrU16(p.registers.sp, value)
+	return func(op *opcode, p *processor) {
+		p.push16(p.registers.getRegisterPair(rp))
 	}
 }
 
 func popRegisterPair(rp RegisterPair) opcodeHandler {
-	return func(op opcode, p *processor) {
-		value := p.memory.ReadAddrU16(p.registers.sp)
+	return func(op *opcode, p *processor) {
+		value := p.readAddrU16(p.registers.sp)
 		p.registers.sp += 2
 		p.registers.setRegisterPair(rp, value)
 	}
 }
 
-func call16BitAddress(op opcode, p *processor) {
+func call16BitAddress(op *opcode, p *processor) {
 	address := p.Read16BitImmediate()
 	doCall16BitAddress(p, address)
 }
 
 func doCall16BitAddress(p *processor, address uint16) {
-	p.registers.sp -= 2
-	p.memory.WriteAddrU16(p.registers.sp, p.registers.pc)
+	p.push16(p.registers.pc)
 	p.registers.pc = address
 }
 
 func conditionalCall16BitAddress(f OpResultFlag, value bool) opcodeHandler {
-	return func(op opcode, p *processor) {
+	return func(op *opcode, p *processor) {
 		address := p.Read16BitImmediate()
 		if p.registers.getFlagValue(f) == value {
-			p.cycles += 12
+			p.branchTaken = true
 			doCall16BitAddress(p, address)
 		}
 	}
 }
 
-func doReturn(op opcode, p *processor) {
-	returnTo := p.memory.ReadAddrU16(p.registers.sp)
+func doReturn(op *opcode, p *processor) {
+	returnTo := p.readAddrU16(p.registers.sp)
 	p.registers.sp += 2
 	p.registers.pc = returnTo
 }
 
-func doReturnEnablingInterrupts(op opcode, p *processor) {
+func doReturnEnablingInterrupts(op *opcode, p *processor) {
+	// unlike EI, RETI enables interrupts straight away
 	p.interruptsEnabled = true
-	if p.savedRegisters != nil {
-		// TODO: check if this is correct? Fixes a NPE but may be incorrect
-		p.registers = p.savedRegisters
-		p.savedRegisters = nil
-	}
+	p.enableInterrupts = false
 	doReturn(op, p)
 }
 
 func conditionalReturn(f OpResultFlag, value bool) opcodeHandler {
-	return func(op opcode, p *processor) {
+	return func(op *opcode, p *processor) {
 		if p.registers.getFlagValue(f) == value {
-			p.cycles += 12
+			p.branchTaken = true
 			doReturn(op, p)
 		}
 	}
 }
 
 func callRoutineAtAddress(address uint16) opcodeHandler {
-	return func(op opcode, p *processor) {
+	return func(op *opcode, p *processor) {
 		doCall16BitAddress(p, address)
 	}
 }
 
-func saveAToFFPlusImmediateAddr(op opcode, p *processor) {
+func saveAToFFPlusImmediateAddr(op *opcode, p *processor) {
 	address := 0xFF00 + uint16(p.Read8BitImmediate())
 	saveAToAddr(address, p)
 }
 
-func saveAToFFPlusCAddr(op opcode, p *processor) {
+func saveAToFFPlusCAddr(op *opcode, p *processor) {
 	address := 0xFF00 + uint16(p.registers.getRegister(RegisterC))
 	saveAToAddr(address, p)
 }
 
-func saveATo16BitAddr(op opcode, p *processor) {
+func saveATo16BitAddr(op *opcode, p *processor) {
 	address := p.Read16BitImmediate()
 	saveAToAddr(address, p)
 }
 
 func saveAToAddr(address uint16, p *processor) {
-	p.memory.WriteAddr(address, p.registers.getRegister(RegisterA))
+	p.writeAddr(address, p.registers.getRegister(RegisterA))
 }
 
-func loadAFromFFPlusImmediateAddr(op opcode, p *processor) {
+func loadAFromFFPlusImmediateAddr(op *opcode, p *processor) {
 	address := 0xFF00 + uint16(p.Read8BitImmediate())
 	doLoadAFromAddr(p, address)
 }
 
-func loadAFromFFPlusC(op opcode, p *processor) {
+func loadAFromFFPlusC(op *opcode, p *processor) {
 	address := 0xFF00 + uint16(p.registers.getRegister(RegisterC))
 	doLoadAFromAddr(p, address)
 }
 
-func loadAFromAddr(op opcode, p *processor) {
+func loadAFromAddr(op *opcode, p *processor) {
 	address := p.Read16BitImmediate()
 	doLoadAFromAddr(p, address)
 }
 
 func doLoadAFromAddr(p *processor, address uint16) {
-	p.registers.setRegister(RegisterA, p.memory.ReadAddr(address))
+	p.registers.setRegister(RegisterA, p.readAddr(address))
 }
 
-func add8BitSignedImmediateToSP(op opcode, p *processor) {
+func add8BitSignedImmediateToSP(op *opcode, p *processor) {
 	doAdd8BitSignedImmediateToSP(p, RegisterPairSP)
 }
 
-func add8BitImmediateToSPSaveInHL(op opcode, p *processor) {
+func add8BitImmediateToSPSaveInHL(op *opcode, p *processor) {
 	doAdd8BitSignedImmediateToSP(p, RegisterPairHL)
 }
 
@@ -644,19 +638,23 @@ func isCarryAdd16Bit(originalValue uint16, operand uint16) bool {
 	return result > 0xFFFF
 }
 
-func copyHLToSP(op opcode, p *processor) {
+func copyHLToSP(op *opcode, p *processor) {
 	p.registers.sp = p.registers.hl
 }
 
-func disableInterrupts(op opcode, p *processor) {
+func disableInterrupts(op *opcode, p *processor) {
 	p.interruptsEnabled = false
+	p.enableInterrupts = false
 }
 
-func enableInterrupts(op opcode, p *processor) {
-	p.interruptsEnabled = true
+func enableInterrupts(op *opcode, p *processor) {
+	// IME is set once the next instruction has run - see DoNextInstruction
+	if !p.interruptsEnabled {
+		p.enableInterrupts = true
+	}
 }
 
-func adjustAForBCDAddition(op opcode, p *processor) {
+func adjustAForBCDAddition(op *opcode, p *processor) {
 	var correction uint8
 
 	flags := FlagNoFlags
@@ -689,30 +687,46 @@ func adjustAForBCDAddition(op opcode, p *processor) {
 	p.registers.setFlags(flags)
 }
 
-func stop(op opcode, p *processor) {
+func stop(op *opcode, p *processor) {
+	// the byte after STOP is skipped
+	p.Read8BitImmediate()
+	p.resetDivider()
+
+	if p.memory != nil && p.memory.SpeedSwitch.IsPrepared() {
+		// a prepared CGB speed switch happens instead of stopping. The real
+		// cpu pauses for a while as the clock settles, which isn't emulated.
+		p.memory.SwitchSpeed()
+		return
+	}
 	p.isStopped = true
 }
 
-func halt(op opcode, p *processor) {
+func halt(op *opcode, p *processor) {
+	if !p.interruptsEnabled && p.pendingInterrupts() != 0 {
+		// with IME clear and an interrupt already pending, halt doesn't
+		// halt - instead it triggers the halt bug
+		p.haltBug = true
+		return
+	}
 	p.isHalted = true
 }
 
-func rotateALeftWithCarry(op opcode, p *processor) {
+func rotateALeftWithCarry(op *opcode, p *processor) {
 	p.registers.setRegister(RegisterA, doRotateLeft(p, p.registers.getRegister(RegisterA), true))
 	p.registers.setFlags(p.registers.getFlags() & ^FlagZ)
 }
 
-func rotateALeft(op opcode, p *processor) {
+func rotateALeft(op *opcode, p *processor) {
 	p.registers.setRegister(RegisterA, doRotateLeft(p, p.registers.getRegister(RegisterA), false))
 	p.registers.setFlags(p.registers.getFlags() & ^FlagZ)
 }
 
-func rotateARightWithCarry(op opcode, p *processor) {
+func rotateARightWithCarry(op *opcode, p *processor) {
 	p.registers.setRegister(RegisterA, doRotateRight(p, p.registers.getRegister(RegisterA), true))
 	p.registers.setFlags(p.registers.getFlags() & ^FlagZ)
 }
 
-func rotateARight(op opcode, p *processor) {
+func rotateARight(op *opcode, p *processor) {
 	p.registers.setRegister(RegisterA, doRotateRight(p, p.registers.getRegister(RegisterA), false))
 	p.registers.setFlags(p.registers.getFlags() & ^FlagZ)
 }
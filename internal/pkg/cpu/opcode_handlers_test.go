@@ -2,6 +2,7 @@ package cpu
 
 import (
 	"fmt"
+	"github.com/mr-tim/goboye/internal/pkg/goboye/button"
 	"github.com/mr-tim/goboye/internal/pkg/memory"
 	"github.com/stretchr/testify/assert"
 	"testing"
@@ -14,6 +15,7 @@ func setupHandlerTest(bytes []byte) *processor {
 	rs := &Registers{}
 	return &processor{
 		registers: rs,
+		bus:       &m,
 		memory:    &m,
 	}
 }
@@ -454,7 +456,7 @@ func doTestJRFlag(t *testing.T, opcode byte, noActionFlag OpResultFlag, actionFl
 	t.Run("No action taken", func(t *testing.T) {
 		p := setupHandlerTest([]byte{opcode, 0x05})
 		setFlag(p, noActionFlag)
-		p.DoNextInstruction()
+		assert.Equal(t, uint8(8), p.DoNextInstruction())
 
 		assert.Equal(t, uint(8), p.Cycles())
 		assert.Equal(t, uint16(2), p.GetRegisterPair(RegisterPairPC))
@@ -462,7 +464,7 @@ func doTestJRFlag(t *testing.T, opcode byte, noActionFlag OpResultFlag, actionFl
 	t.Run("Action taken", func(t *testing.T) {
 		p := setupHandlerTest([]byte{opcode, 0x05})
 		setFlag(p, actionFlag)
-		p.DoNextInstruction()
+		assert.Equal(t, uint8(12), p.DoNextInstruction())
 
 		assert.Equal(t, uint(12), p.Cycles())
 		assert.Equal(t, uint16(7), p.GetRegisterPair(RegisterPairPC))
@@ -576,7 +578,6 @@ func TestRet(t *testing.T) {
 
 func TestRetI(t *testing.T) {
 	p := setupHandlerTest([]byte{0xD9, 0x00, 0x00, 0x00, 0x00, 0x00, 0x59, 0x83, 0x45, 0x67})
-	p.savedRegisters = p.registers
 	p.registers.setRegisterPair(RegisterPairSP, uint16(6))
 	p.interruptsEnabled = false
 	p.DoNextInstruction()
@@ -635,12 +636,157 @@ func TestDisableInterrupts(t *testing.T) {
 }
 
 func TestEnableInterrupts(t *testing.T) {
-	p := setupHandlerTest([]byte{0xFB})
+	p := setupHandlerTest([]byte{0xFB, 0x00})
 	p.interruptsEnabled = false
 	p.DoNextInstruction()
+	// delayed by one instruction
+	assert.Equal(t, false, p.interruptsEnabled)
+	p.DoNextInstruction()
 	assert.Equal(t, true, p.interruptsEnabled)
 }
 
+func TestEnableThenDisableInterrupts(t *testing.T) {
+	p := setupHandlerTest([]byte{0xFB, 0xF3, 0x00})
+	p.DoNextInstruction()
+	p.DoNextInstruction()
+	p.DoNextInstruction()
+	assert.Equal(t, false, p.interruptsEnabled)
+}
+
+func TestInterruptWaitsForInstructionAfterEnable(t *testing.T) {
+	p := setupHandlerTest([]byte{0xFB, 0x04, 0x04})
+	p.registers.sp = 0xFFFE
+	p.memory.InterruptEnabled.Write(0x01)
+	p.memory.InterruptFlags.VBlankInterrupt()
+
+	p.DoNextInstruction()
+	p.DoNextInstruction()
+	assert.Equal(t, uint8(0x01), p.registers.getRegister(RegisterB))
+
+	assert.Equal(t, uint8(20), p.DoNextInstruction())
+	assert.Equal(t, uint16(0x0040), p.registers.pc)
+	assert.Equal(t, uint16(0x0002), p.memory.ReadAddrU16(p.registers.sp))
+	assert.Equal(t, false, p.interruptsEnabled)
+}
+
+func TestInterruptOnlyClearsServicedFlag(t *testing.T) {
+	p := setupHandlerTest([]byte{0x00})
+	p.registers.sp = 0xFFFE
+	p.interruptsEnabled = true
+	p.memory.InterruptEnabled.Write(0x05)
+	// timer and serial are requested, but serial isn't enabled
+	p.memory.InterruptFlags.Write(0x0C)
+
+	p.DoNextInstruction()
+	assert.Equal(t, uint16(0x0050), p.registers.pc)
+	assert.Equal(t, uint8(0x08), p.memory.InterruptFlags.Read())
+}
+
+func TestInterruptCancelledByIePush(t *testing.T) {
+	p := setupHandlerTest([]byte{0x00})
+	// pushing pc's high byte (0x00) to 0xFFFF clears IE
+	p.registers.sp = 0x0000
+	p.interruptsEnabled = true
+	p.memory.InterruptEnabled.Write(0x01)
+	p.memory.InterruptFlags.VBlankInterrupt()
+
+	p.DoNextInstruction()
+	assert.Equal(t, uint16(0x0000), p.registers.pc)
+	assert.True(t, p.memory.InterruptFlags.VBlank())
+}
+
+func TestHaltWakesOnPendingInterruptWithoutIme(t *testing.T) {
+	p := setupHandlerTest([]byte{0x76, 0x04})
+	p.memory.InterruptEnabled.Write(0x04)
+	p.DoNextInstruction()
+	assert.True(t, p.IsHalted())
+	assert.Equal(t, uint16(0x0001), p.registers.pc)
+
+	// time passes while halted
+	assert.Equal(t, uint8(4), p.DoNextInstruction())
+	assert.True(t, p.IsHalted())
+	assert.Equal(t, uint16(0x0001), p.registers.pc)
+
+	// without IME the cpu wakes and carries on, leaving the interrupt pending
+	p.memory.InterruptFlags.TimerOverflowInterrupt()
+	p.DoNextInstruction()
+	assert.False(t, p.IsHalted())
+	assert.Equal(t, uint8(0x01), p.registers.getRegister(RegisterB))
+	assert.Equal(t, uint16(0x0002), p.registers.pc)
+	assert.True(t, p.memory.InterruptFlags.TimerOverflow())
+}
+
+func TestHaltWakesIntoInterruptWithIme(t *testing.T) {
+	p := setupHandlerTest([]byte{0x76, 0x04})
+	p.registers.sp = 0xFFFE
+	p.interruptsEnabled = true
+	p.memory.InterruptEnabled.Write(0x04)
+	p.DoNextInstruction()
+	assert.True(t, p.IsHalted())
+
+	p.memory.InterruptFlags.TimerOverflowInterrupt()
+	p.DoNextInstruction()
+	assert.False(t, p.IsHalted())
+	assert.Equal(t, uint16(0x0050), p.registers.pc)
+	assert.Equal(t, uint16(0x0001), p.memory.ReadAddrU16(p.registers.sp))
+}
+
+func TestHaltBug(t *testing.T) {
+	p := setupHandlerTest([]byte{0x76, 0x04, 0x00})
+	p.memory.InterruptEnabled.Write(0x04)
+	p.memory.InterruptFlags.TimerOverflowInterrupt()
+
+	// halt with IME clear and an interrupt pending doesn't halt...
+	p.DoNextInstruction()
+	assert.False(t, p.IsHalted())
+
+	// ...but the byte after it is read twice
+	p.DoNextInstruction()
+	assert.Equal(t, uint16(0x0001), p.registers.pc)
+	p.DoNextInstruction()
+	assert.Equal(t, uint16(0x0002), p.registers.pc)
+	assert.Equal(t, uint8(0x02), p.registers.getRegister(RegisterB))
+}
+
+func TestStopWaitsForJoypad(t *testing.T) {
+	p := setupHandlerTest([]byte{0x10, 0x00, 0x04})
+	for i := 0; i < 0x200; i++ {
+		p.memory.Scheduler().Advance(4, p.memory)
+	}
+	// select both button rows
+	p.memory.WriteAddr(0xFF00, 0x00)
+
+	p.DoNextInstruction()
+	assert.True(t, p.IsStopped())
+	assert.Equal(t, uint16(0x0002), p.registers.pc)
+	assert.Equal(t, uint8(0x00), p.memory.Timer.ReadDivider())
+
+	assert.Equal(t, uint8(0), p.DoNextInstruction())
+	assert.True(t, p.IsStopped())
+
+	p.memory.ControllerData.SetButtonState(button.Start, true)
+	p.DoNextInstruction()
+	assert.False(t, p.IsStopped())
+	assert.Equal(t, uint8(0x01), p.registers.getRegister(RegisterB))
+}
+
+func TestStopSwitchesCgbSpeed(t *testing.T) {
+	p := setupHandlerTest([]byte{0x10, 0x00, 0x10, 0x00})
+	p.memory.SetModel(memory.CGB)
+	assert.Equal(t, NormalSpeed, p.SpeedMode())
+
+	p.memory.WriteAddr(0xFF4D, 0x01)
+	p.DoNextInstruction()
+	assert.False(t, p.IsStopped())
+	assert.Equal(t, DoubleSpeed, p.SpeedMode())
+	assert.Equal(t, uint8(0xFE), p.memory.ReadAddr(0xFF4D))
+
+	// without a prepared switch, it's a normal stop
+	p.DoNextInstruction()
+	assert.True(t, p.IsStopped())
+	assert.Equal(t, DoubleSpeed, p.SpeedMode())
+}
+
 func TestXorA(t *testing.T) {
 	doTestXorReg(t, RegisterA, 0xAF)
 }
@@ -1000,3 +1146,114 @@ func TestADCnExhaustive(t *testing.T) {
 		}
 	}
 }
+
+func TestFormatCycles(t *testing.T) {
+	jr := OpcodeAndPayload{op: &OpcodeJrNzn, payload: []byte{0x05}}
+	assert.Equal(t, "8/12", jr.FormatCycles())
+	nop := OpcodeAndPayload{op: &OpcodeNop}
+	assert.Equal(t, "4", nop.FormatCycles())
+}
+
+// countingTicker records ticks, and counts them in work ram so tests can see
+// which M-cycle a memory access happened on.
+type countingTicker struct {
+	p     *processor
+	ticks []uint8
+}
+
+func (t *countingTicker) Tick(cycles uint8) {
+	t.ticks = append(t.ticks, cycles)
+	t.p.memory.WriteAddr(0xC000, t.p.memory.ReadAddr(0xC000)+1)
+}
+
+func TestCycleAccurateRead(t *testing.T) {
+	// LD A,(HL)
+	p := setupHandlerTest([]byte{0x7E})
+	p.registers.setRegisterPair(RegisterPairHL, 0xC000)
+	ticker := &countingTicker{p: p}
+	p.SetTicker(ticker)
+
+	cycles := p.DoNextInstruction()
+
+	assert.Equal(t, uint8(8), cycles)
+	assert.Equal(t, []uint8{4, 4}, ticker.ticks)
+	// the read sees both the opcode fetch and its own M-cycle
+	assert.Equal(t, uint8(2), p.registers.getRegister(RegisterA))
+}
+
+func TestCycleAccurateTicksInternalCycles(t *testing.T) {
+	// PUSH BC - a fetch, an internal cycle, then two writes
+	p := setupHandlerTest([]byte{0xC5})
+	p.registers.sp = 0xD000
+	ticker := &countingTicker{p: p}
+	p.SetTicker(ticker)
+
+	cycles := p.DoNextInstruction()
+
+	assert.Equal(t, uint8(16), cycles)
+	total := uint8(0)
+	for _, c := range ticker.ticks {
+		total += c
+	}
+	assert.Equal(t, cycles, total)
+}
+
+func TestCycleAccurateInterruptDispatch(t *testing.T) {
+	p := setupHandlerTest([]byte{0x00})
+	p.registers.sp = 0xD000
+	p.interruptsEnabled = true
+	p.memory.InterruptEnabled.Write(0x01)
+	p.memory.InterruptFlags.Write(0x01)
+	ticker := &countingTicker{p: p}
+	p.SetTicker(ticker)
+
+	cycles := p.DoNextInstruction()
+
+	assert.Equal(t, uint8(interruptDispatchCycles), cycles)
+	assert.Equal(t, []uint8{8, 4, 4, 4}, ticker.ticks)
+	assert.Equal(t, uint16(0x0040), p.registers.pc)
+}
+
+func TestExtendedOpcodeCycles(t *testing.T) {
+	tests := []struct {
+		name   string
+		code   byte
+		cycles uint8
+	}{
+		{"RLC B", 0x00, 8},
+		{"BIT 0,(HL)", 0x46, 12},
+		{"RLC (HL)", 0x06, 16},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			p := setupHandlerTest([]byte{0xCB, test.code})
+			p.registers.setRegisterPair(RegisterPairHL, 0xC000)
+
+			assert.Equal(t, test.cycles, p.DoNextInstruction())
+			assert.Equal(t, uint16(2), p.registers.pc)
+		})
+	}
+}
+
+func TestIllegalOpcodeLocksUp(t *testing.T) {
+	for _, code := range []byte{0xD3, 0xDB, 0xDD, 0xE3, 0xE4, 0xEB, 0xEC, 0xED, 0xF4, 0xFC, 0xFD} {
+		t.Run(fmt.Sprintf("%02X", code), func(t *testing.T) {
+			p := setupHandlerTest([]byte{0x00, code, 0x00})
+			p.DoNextInstruction()
+			_, locked := p.Lockup()
+			assert.False(t, locked)
+
+			p.DoNextInstruction()
+			lockup, locked := p.Lockup()
+			assert.True(t, locked)
+			assert.Equal(t, IllegalOpcode{Address: 0x0001, Opcode: code}, lockup)
+
+			// nothing runs after it, not even an interrupt
+			p.interruptsEnabled = true
+			p.memory.InterruptEnabled.Write(0x01)
+			p.memory.InterruptFlags.Write(0x01)
+			assert.Equal(t, uint8(4), p.DoNextInstruction())
+			assert.Equal(t, uint16(0x0002), p.registers.pc)
+		})
+	}
+}
@@ -1,6 +1,7 @@
 package cpu
 
 import (
+	"fmt"
 	"github.com/mr-tim/goboye/internal/pkg/memory"
 	"github.com/mr-tim/goboye/internal/pkg/utils"
 )
@@ -11,68 +12,230 @@ type Processor interface {
 	DebugRegisters() Registers
 	GetRegister(reg register) uint8
 	GetRegisterPair(pair RegisterPair) uint16
+	SetRegister(reg register, value uint8)
+	SetRegisterPair(pair RegisterPair, value uint16)
 	GetFlagValue(flagName OpResultFlag) bool
 	Cycles() uint
+	// InterruptsEnabled is IME, the interrupt master enable flag.
+	InterruptsEnabled() bool
+	SetInterruptsEnabled(enabled bool)
 	IsStopped() bool
 	IsHalted() bool
+	SpeedMode() SpeedMode
+	// Lockup returns the illegal opcode that hung the cpu, if it has run one.
+	Lockup() (IllegalOpcode, bool)
+	// SetTicker switches to cycle accurate mode, where ticker is ticked on
+	// every memory access. A nil ticker goes back to the faster mode that
+	// leaves the caller to catch up after each instruction.
+	SetTicker(ticker Ticker)
+	// SetDecodeCache turns on caching of instructions decoded from rom.
+	SetDecodeCache(enabled bool)
+}
+
+// SpeedMode is how fast the cpu runs relative to the display. Only the CGB
+// can switch to double speed.
+type SpeedMode int
+
+const (
+	NormalSpeed SpeedMode = iota
+	DoubleSpeed
+)
+
+// IllegalOpcode is one of the undefined opcodes, run at Address. Running one
+// locks up the cpu.
+type IllegalOpcode struct {
+	Address uint16
+	Opcode  byte
+}
+
+func (i IllegalOpcode) String() string {
+	return fmt.Sprintf("illegal opcode %02X at %04X", i.Opcode, i.Address)
 }
 
 type processor struct {
-	registers         *Registers
-	savedRegisters    *Registers
+	registers *Registers
+	bus       Bus
+	// memory is the memory controller, if the bus is one
 	memory            *memory.Controller
 	cycles            uint
 	interruptsEnabled bool
+	enableInterrupts  bool
+	branchTaken       bool
 	isHalted          bool
+	haltBug           bool
 	isStopped         bool
+	lockup            *IllegalOpcode
+	ticker            Ticker
+	ticked            uint8
+	decodeCache       *decodeCache
 }
 
 func NewProcessor(memory *memory.Controller) Processor {
 	p := processor{
 		registers: &Registers{},
+		bus:       memory,
 		memory:    memory,
 	}
 	p.registers.pc = uint16(0x0000)
 	return &p
 }
 
-func (p *processor) readNextInstruction() opcode {
-	opCodeByte := p.Read8BitImmediate()
+// NewProcessorWithBus returns a processor running from bus alone, without the
+// rest of the hardware. Interrupts, STOP and the divider go through their io
+// register addresses on the bus, and there's no CGB speed switching.
+func NewProcessorWithBus(bus Bus) Processor {
+	return &processor{
+		registers: &Registers{},
+		bus:       bus,
+	}
+}
+
+// NewProcessorAfterBoot returns a processor in the state the boot rom leaves
+// it in, ready to run the cartridge from 0x0100. Pair it with
+// memory.Controller's SkipBootRom.
+func NewProcessorAfterBoot(memory *memory.Controller) Processor {
+	p := processor{
+		registers: postBootRegisters(memory.Model(), memory.CartridgeHeader().HeaderChecksum),
+		bus:       memory,
+		memory:    memory,
+	}
+	return &p
+}
+
+// postBootRegisters are the register values each model's boot rom hands over
+// with. The DMG and MGB only set the H and C flags if the header checksum is
+// non-zero.
+func postBootRegisters(model memory.Model, headerChecksum byte) *Registers {
+	r := Registers{sp: 0xFFFE, pc: 0x0100}
+	switch model {
+	case memory.SGB:
+		r.af, r.bc, r.de, r.hl = 0x0100, 0x0014, 0x0000, 0xC060
+	case memory.CGB:
+		r.af, r.bc, r.de, r.hl = 0x1180, 0x0000, 0xFF56, 0x000D
+	default:
+		r.af, r.bc, r.de, r.hl = 0x0180, 0x0013, 0x00D8, 0x014D
+		if model == memory.MGB {
+			r.af = 0xFF80
+		}
+		if headerChecksum != 0x00 {
+			r.af |= uint16(FlagH | FlagC)
+		}
+	}
+	return &r
+}
+
+func (p *processor) readNextInstruction() *opcode {
+	if p.haltBug {
+		// the halt bug - pc fails to move past the opcode, so the next byte
+		// is read twice
+		p.haltBug = false
+		return p.decode(p.readAddr(p.registers.pc))
+	}
+	if p.decodeCache == nil || p.memory == nil {
+		return p.decode(p.Read8BitImmediate())
+	}
+
+	addr := p.registers.pc
+	version := p.memory.RomMapVersion()
+	if o, length := p.decodeCache.lookup(addr, version); o != nil {
+		// the fetches still take the same time
+		p.tick(length * mCycle)
+		p.registers.pc += uint16(length)
+		return o
+	}
+	o := p.decode(p.Read8BitImmediate())
+	p.decodeCache.store(addr, o, uint8(p.registers.pc-addr), version)
+	return o
+}
+
+func (p *processor) decode(opCodeByte byte) *opcode {
+	if opCodeByte == OpcodeExtOps.code {
+		// CB prefixed instructions are looked up by their second byte, which
+		// also gives their timing
+		return LookupExtOpcode(p.Read8BitImmediate())
+	}
 	return LookupOpcode(opCodeByte)
 }
 
-func (p *processor) peekNextInstruction() opcode {
-	b := p.memory.ReadAddr(p.registers.pc)
+func (p *processor) peekNextInstruction() *opcode {
+	b := p.bus.ReadAddr(p.registers.pc)
 	return LookupOpcode(b)
 }
 
 func (p *processor) NextInstruction() Opcode {
-	o := p.peekNextInstruction()
-	return &o
+	return p.peekNextInstruction()
 }
 
 func (p *processor) Read8BitImmediate() byte {
-	value := p.memory.ReadAddr(p.registers.pc)
+	value := p.readAddr(p.registers.pc)
 	p.registers.pc++
 	return value
 }
 
 func (p *processor) Read16BitImmediate() uint16 {
-	value := p.memory.ReadAddrU16(p.registers.pc)
+	value := p.readAddrU16(p.registers.pc)
 	p.registers.pc += 2
 	return value
 }
 
+// haltCycles is how long the cpu idles for each time it checks for a pending
+// interrupt while halted.
+const haltCycles = 4
+
 func (p *processor) DoNextInstruction() uint8 {
-	if !p.HandleInterrupts() {
-		o := p.readNextInstruction()
-		o.handler(o, p)
-		p.cycles += uint(o.Cycles())
-		return o.Cycles()
-	} else {
-		// TODO: how many cycles?
-		return 0
+	if p.lockup != nil {
+		// a locked up cpu ignores interrupts, but the rest of the system
+		// keeps running
+		p.tick(haltCycles)
+		p.ticked = 0
+		p.cycles += haltCycles
+		return haltCycles
+	}
+
+	if p.isStopped {
+		// everything is stopped until a button is pressed
+		if !p.isJoypadLineLow() {
+			return 0
+		}
+		p.isStopped = false
 	}
+
+	if p.isHalted {
+		// time keeps passing while halted, so the timer and display can
+		// raise the interrupt that wakes the cpu
+		if p.pendingInterrupts() == 0 {
+			p.tick(haltCycles)
+			p.ticked = 0
+			p.cycles += haltCycles
+			return haltCycles
+		}
+		p.isHalted = false
+	}
+
+	if p.HandleInterrupts() {
+		p.tickRemaining(interruptDispatchCycles)
+		p.cycles += interruptDispatchCycles
+		return interruptDispatchCycles
+	}
+
+	// EI only takes effect after the instruction that follows it
+	enableInterrupts := p.enableInterrupts
+	o := p.readNextInstruction()
+	p.branchTaken = false
+	o.handler(o, p)
+	if enableInterrupts && p.enableInterrupts {
+		p.interruptsEnabled = true
+		p.enableInterrupts = false
+	}
+
+	// conditional handlers set branchTaken when their condition holds
+	cycles := o.Cycles()
+	if p.branchTaken {
+		cycles = o.TakenCycles()
+	}
+	p.tickRemaining(cycles)
+	p.cycles += uint(cycles)
+	return cycles
 }
 
 func (p *processor) DebugRegisters() Registers {
@@ -87,6 +250,14 @@ func (p *processor) GetRegisterPair(regPair RegisterPair) uint16 {
 	return p.registers.getRegisterPair(regPair)
 }
 
+func (p *processor) SetRegister(reg register, value uint8) {
+	p.registers.setRegister(reg, value)
+}
+
+func (p *processor) SetRegisterPair(regPair RegisterPair, value uint16) {
+	p.registers.setRegisterPair(regPair, value)
+}
+
 func (p *processor) GetFlagValue(flagName OpResultFlag) bool {
 	return p.registers.getFlagValue(flagName)
 }
@@ -95,26 +266,56 @@ func (p *processor) Cycles() uint {
 	return p.cycles
 }
 
-func (p *processor) HandleInterrupts() bool {
-	if p.interruptsEnabled {
-		eif := p.memory.InterruptEnabled.Read() & p.memory.InterruptFlags.Read()
-		addr, flagIndex := memory.GetIsrAddress(eif)
-		p.memory.InterruptFlags.Write(utils.UnsetBit(eif, flagIndex))
-		if addr != 0x0000 {
-			p.serviceInterrupt(addr)
-			return true
-		}
-	}
-	return false
+func (p *processor) InterruptsEnabled() bool {
+	return p.interruptsEnabled
 }
 
-func (p *processor) serviceInterrupt(address memory.InterruptAddress) {
-	//save Registers
-	p.savedRegisters = p.registers
-	//disable interrupts
+func (p *processor) SetInterruptsEnabled(enabled bool) {
+	p.interruptsEnabled = enabled
+	p.enableInterrupts = false
+}
+
+// pendingInterrupts are the interrupts that are both enabled and requested.
+// They wake a halted cpu whether or not IME is set.
+func (p *processor) pendingInterrupts() byte {
+	enabled, flags := p.interruptRegisters()
+	return enabled & flags & 0x1F
+}
+
+// interruptDispatchCycles is how long it takes to call an interrupt handler:
+// two idle M-cycles, two to push pc and one to jump.
+const interruptDispatchCycles = 20
+
+// HandleInterrupts calls the handler for the highest priority pending
+// interrupt, if IME is set. It reports whether a handler was called.
+func (p *processor) HandleInterrupts() bool {
+	if !p.interruptsEnabled || p.pendingInterrupts() == 0 {
+		return false
+	}
 	p.interruptsEnabled = false
-	//call isr
-	doCall16BitAddress(p, uint16(address))
+	if p.haltBug {
+		// with EI just before a buggy HALT, the handler returns to the HALT
+		p.haltBug = false
+		p.registers.pc--
+	}
+
+	// the interrupt to service is picked after the high byte of pc has
+	// been pushed - if that push overwrote IE (sp was 0x0000), the pick uses
+	// the new value, and with nothing left to service pc ends up at 0x0000
+	p.tick(2 * mCycle)
+	p.registers.sp--
+	p.writeAddr(p.registers.sp, byte(p.registers.pc>>8))
+	addr, flagIndex := memory.GetIsrAddress(p.pendingInterrupts())
+	p.registers.sp--
+	p.writeAddr(p.registers.sp, byte(p.registers.pc))
+
+	p.registers.pc = uint16(addr)
+	if addr != 0x0000 {
+		// only the serviced interrupt is acknowledged - others stay pending
+		_, flags := p.interruptRegisters()
+		p.writeInterruptFlags(utils.UnsetBit(flags, flagIndex))
+	}
+	return true
 }
 
 func (p *processor) IsHalted() bool {
@@ -124,3 +325,17 @@ func (p *processor) IsHalted() bool {
 func (p *processor) IsStopped() bool {
 	return p.isStopped
 }
+
+func (p *processor) Lockup() (IllegalOpcode, bool) {
+	if p.lockup == nil {
+		return IllegalOpcode{}, false
+	}
+	return *p.lockup, true
+}
+
+func (p *processor) SpeedMode() SpeedMode {
+	if p.memory != nil && p.memory.SpeedSwitch.IsDoubleSpeed() {
+		return DoubleSpeed
+	}
+	return NormalSpeed
+}
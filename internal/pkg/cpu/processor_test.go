@@ -0,0 +1,143 @@
+package cpu
+
+import (
+	"github.com/mr-tim/goboye/internal/pkg/memory"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+// benchmarkProgram is a small loop of loads, arithmetic and CB ops over work
+// ram, something like a game's inner loop.
+var benchmarkProgram = []byte{
+	0x21, 0x00, 0xC0, // LD HL,0xC000
+	0x06, 0x00, // LD B,0x00
+	0x2A,       // LD A,(HL+)
+	0x80,       // ADD A,B
+	0xCB, 0x37, // SWAP A
+	0x77,       // LD (HL),A
+	0x05,       // DEC B
+	0x20, 0xF8, // JR NZ,-8
+	0xC3, 0x00, 0x00, // JP 0x0000
+}
+
+func setupBenchmark() (*memory.Controller, Processor) {
+	m := memory.NewControllerWithBytes(benchmarkProgram)
+	// disable the boot rom
+	m.BootRomRegister.Write(0x01)
+	return &m, NewProcessor(&m)
+}
+
+func runBenchmark(b *testing.B, p Processor) {
+	b.ResetTimer()
+	start := time.Now()
+	for i := 0; i < b.N; i++ {
+		p.DoNextInstruction()
+	}
+	b.ReportMetric(float64(b.N)/time.Since(start).Seconds(), "instructions/s")
+}
+
+func BenchmarkDoNextInstruction(b *testing.B) {
+	_, p := setupBenchmark()
+	runBenchmark(b, p)
+}
+
+func BenchmarkDoNextInstructionDecodeCache(b *testing.B) {
+	_, p := setupBenchmark()
+	p.SetDecodeCache(true)
+	runBenchmark(b, p)
+}
+
+type nopTicker struct{}
+
+func (nopTicker) Tick(cycles uint8) {}
+
+func BenchmarkDoNextInstructionCycleAccurate(b *testing.B) {
+	_, p := setupBenchmark()
+	p.SetTicker(nopTicker{})
+	runBenchmark(b, p)
+}
+
+func TestDecodeCacheInvalidatedByBankSwitch(t *testing.T) {
+	// an MBC1 rom with NOP at the start of bank 1, and INC A in bank 2
+	rom := make([]byte, 4*0x4000)
+	rom[0x0147] = 0x01
+	rom[0x8000] = 0x3C
+	m := memory.NewControllerWithBytes(rom)
+	m.BootRomRegister.Write(0x01)
+	p := &processor{registers: &Registers{}, bus: &m, memory: &m}
+	p.SetDecodeCache(true)
+
+	runAt := func(addr uint16) {
+		p.registers.pc = addr
+		p.DoNextInstruction()
+	}
+
+	runAt(0x4000)
+	runAt(0x4000)
+	assert.Equal(t, uint8(0x00), p.registers.getRegister(RegisterA))
+
+	m.WriteAddr(0x2000, 0x02)
+	runAt(0x4000)
+	assert.Equal(t, uint8(0x01), p.registers.getRegister(RegisterA))
+	assert.Equal(t, uint16(0x4001), p.registers.pc)
+}
+
+func TestDecodeCacheExtendedOpcode(t *testing.T) {
+	// SWAP A, twice from the same address
+	p := setupHandlerTest([]byte{0xCB, 0x37})
+	p.SetDecodeCache(true)
+	p.registers.setRegister(RegisterA, 0x12)
+
+	assert.Equal(t, uint8(8), p.DoNextInstruction())
+	p.registers.pc = 0
+	assert.Equal(t, uint8(8), p.DoNextInstruction())
+	assert.Equal(t, uint8(0x12), p.registers.getRegister(RegisterA))
+	assert.Equal(t, uint16(0x0002), p.registers.pc)
+}
+
+// flatBus is 64KiB of ram, recording each write.
+type flatBus struct {
+	mem    [0x10000]byte
+	writes []uint16
+}
+
+func (b *flatBus) ReadAddr(addr uint16) byte {
+	return b.mem[addr]
+}
+
+func (b *flatBus) WriteAddr(addr uint16, value byte) {
+	b.mem[addr] = value
+	b.writes = append(b.writes, addr)
+}
+
+func TestProcessorWithBus(t *testing.T) {
+	bus := &flatBus{}
+	// PUSH BC at an address that's rom on a real cartridge
+	bus.mem[0x1234] = 0xC5
+	p := NewProcessorWithBus(bus)
+	p.SetRegisterPair(RegisterPairPC, 0x1234)
+	p.SetRegisterPair(RegisterPairSP, 0x0002)
+	p.SetRegisterPair(RegisterPairBC, 0xABCD)
+
+	assert.Equal(t, uint8(16), p.DoNextInstruction())
+	assert.Equal(t, uint16(0x0000), p.GetRegisterPair(RegisterPairSP))
+	assert.Equal(t, []byte{0xCD, 0xAB}, bus.mem[0:2])
+	// the high byte is pushed first
+	assert.Equal(t, []uint16{0x0001, 0x0000}, bus.writes)
+}
+
+func TestProcessorWithBusInterrupts(t *testing.T) {
+	bus := &flatBus{}
+	bus.mem[0xFFFF] = 0x04
+	bus.mem[0xFF0F] = 0x05
+	p := NewProcessorWithBus(bus)
+	p.SetRegisterPair(RegisterPairSP, 0xD000)
+	p.SetInterruptsEnabled(true)
+
+	p.DoNextInstruction()
+
+	assert.Equal(t, uint16(0x0050), p.GetRegisterPair(RegisterPairPC))
+	assert.Equal(t, byte(0x01), bus.mem[0xFF0F])
+	assert.False(t, p.InterruptsEnabled())
+}
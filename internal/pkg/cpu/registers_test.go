@@ -1,6 +1,7 @@
 package cpu
 
 import (
+	"github.com/mr-tim/goboye/internal/pkg/memory"
 	"github.com/stretchr/testify/assert"
 	"testing"
 )
@@ -71,3 +72,20 @@ func TestRegisterPairEndianness(t *testing.T) {
 		assert.Equal(t, regExpected, r.getRegister(rp[1]))
 	}
 }
+
+func TestPostBootRegisters(t *testing.T) {
+	r := postBootRegisters(memory.DMG, 0x4D)
+	assert.Equal(t, uint16(0x01B0), r.getRegisterPair(RegisterPairAF))
+	assert.Equal(t, uint16(0x0013), r.getRegisterPair(RegisterPairBC))
+	assert.Equal(t, uint16(0x00D8), r.getRegisterPair(RegisterPairDE))
+	assert.Equal(t, uint16(0x014D), r.getRegisterPair(RegisterPairHL))
+	assert.Equal(t, uint16(0xFFFE), r.getRegisterPair(RegisterPairSP))
+	assert.Equal(t, uint16(0x0100), r.getRegisterPair(RegisterPairPC))
+
+	r = postBootRegisters(memory.DMG, 0x00)
+	assert.Equal(t, uint16(0x0180), r.getRegisterPair(RegisterPairAF))
+
+	r = postBootRegisters(memory.CGB, 0x4D)
+	assert.Equal(t, uint16(0x1180), r.getRegisterPair(RegisterPairAF))
+	assert.Equal(t, uint16(0xFF56), r.getRegisterPair(RegisterPairDE))
+}
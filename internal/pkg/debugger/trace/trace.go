@@ -0,0 +1,54 @@
+// Package trace writes execution traces in the Gameboy Doctor format, one
+// line per instruction, so a run can be diffed against reference logs:
+//
+//	A:01 F:B0 B:00 C:13 D:00 E:D8 H:01 L:4D SP:FFFE PC:0100 PCMEM:00,C3,13,02
+//
+// Gameboy Doctor's logs are made with LY (0xFF44) always reading 0x90, so
+// they'll only match line for line with Emulator.SetDoctor on.
+package trace
+
+import (
+	"bufio"
+	"fmt"
+	"github.com/mr-tim/goboye/internal/pkg/cpu"
+	"io"
+	"sync"
+)
+
+// Memory is what the trace reads the bytes at PC from. Reads mustn't have
+// side effects.
+type Memory interface {
+	ReadAddr(addr uint16) byte
+}
+
+// Writer is safe to share between emulators - each line is written whole.
+type Writer struct {
+	mu sync.Mutex
+	w  *bufio.Writer
+}
+
+func NewWriter(w io.Writer) *Writer {
+	return &Writer{w: bufio.NewWriter(w)}
+}
+
+// Write traces the processor's state before it runs its next instruction.
+func (t *Writer) Write(p cpu.Processor, m Memory) error {
+	af := p.GetRegisterPair(cpu.RegisterPairAF)
+	bc := p.GetRegisterPair(cpu.RegisterPairBC)
+	de := p.GetRegisterPair(cpu.RegisterPairDE)
+	hl := p.GetRegisterPair(cpu.RegisterPairHL)
+	pc := p.GetRegisterPair(cpu.RegisterPairPC)
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	_, err := fmt.Fprintf(t.w, "A:%02X F:%02X B:%02X C:%02X D:%02X E:%02X H:%02X L:%02X SP:%04X PC:%04X PCMEM:%02X,%02X,%02X,%02X\n",
+		af>>8, af&0xFF, bc>>8, bc&0xFF, de>>8, de&0xFF, hl>>8, hl&0xFF,
+		p.GetRegisterPair(cpu.RegisterPairSP), pc,
+		m.ReadAddr(pc), m.ReadAddr(pc+1), m.ReadAddr(pc+2), m.ReadAddr(pc+3))
+	return err
+}
+
+func (t *Writer) Flush() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.w.Flush()
+}
@@ -0,0 +1,29 @@
+package trace
+
+import (
+	"bytes"
+	"github.com/mr-tim/goboye/internal/pkg/cpu"
+	"github.com/mr-tim/goboye/internal/pkg/memory"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestWrite(t *testing.T) {
+	rom := make([]byte, 0x8000)
+	copy(rom[0x0100:], []byte{0x00, 0xC3, 0x13, 0x02})
+	// a non-zero header checksum leaves H and C set after boot
+	rom[0x014D] = 0x01
+	m := memory.NewControllerWithBytes(rom)
+	m.SkipBootRom()
+	p := cpu.NewProcessorAfterBoot(&m)
+
+	b := new(bytes.Buffer)
+	w := NewWriter(b)
+	assert.NoError(t, w.Write(p, &m))
+	p.DoNextInstruction()
+	assert.NoError(t, w.Write(p, &m))
+	assert.NoError(t, w.Flush())
+
+	assert.Equal(t, "A:01 F:B0 B:00 C:13 D:00 E:D8 H:01 L:4D SP:FFFE PC:0100 PCMEM:00,C3,13,02\n"+
+		"A:01 F:B0 B:00 C:13 D:00 E:D8 H:01 L:4D SP:FFFE PC:0101 PCMEM:C3,13,02,00\n", b.String())
+}
@@ -34,6 +34,9 @@ func (w *RegistersWidget) Layout(g *gocui.Gui) error {
 	fmt.Fprintf(v, " n = %s\n", w.getOpFlag(cpu.FlagN))
 	fmt.Fprintf(v, " h = %s\n", w.getOpFlag(cpu.FlagH))
 	fmt.Fprintf(v, " c = %s\n", w.getOpFlag(cpu.FlagC))
+	fmt.Fprintf(v, "\n")
+	fmt.Fprintf(v, " bank = %02x\n", w.emulator.GetRomBank())
+	fmt.Fprintf(v, " clock = %d\n", w.emulator.Clock())
 	return nil
 }
 
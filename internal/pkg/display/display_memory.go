@@ -2,7 +2,6 @@ package display
 
 import (
 	"fmt"
-	"github.com/mr-tim/goboye/internal/pkg/display/register"
 	"github.com/mr-tim/goboye/internal/pkg/memory"
 	"github.com/mr-tim/goboye/internal/pkg/utils"
 	"image"
@@ -113,26 +112,51 @@ import (
 */
 
 const FRAMES_PER_SECOND = 60
-const CYCLES_PER_FRAME = utils.CPU_CYCLES_PER_SECOND / FRAMES_PER_SECOND
 const COLS = 160
 const ROWS = 144
 const VBLANK_ROWS = 10
 const TOTAL_ROWS = ROWS + VBLANK_ROWS
-const CYCLES_PER_LINE = CYCLES_PER_FRAME / TOTAL_ROWS
+const CYCLES_PER_LINE = 456
+const CYCLES_PER_FRAME = CYCLES_PER_LINE * TOTAL_ROWS
+const OAM_SEARCH_CYCLES = 80
+const TRANSFER_CYCLES = 172
 
 func NewDisplay(m *memory.Controller) Display {
-	return Display{
-		m: m,
+	d := Display{
+		m:     m,
+		lcdOn: m.LCDCFlags.IsLCDEnabled(),
+		frame: newFrame(),
+		back:  newFrame(),
 	}
+	if d.lcdOn {
+		d.startLine(0)
+	} else {
+		d.switchOff()
+	}
+	d.m.UpdateStatInterrupt()
+	return d
 }
 
 type Display struct {
 	m         *memory.Controller
-	cycles    int
 	bgChars   []image.PalettedImage
 	pal0Chars []image.PalettedImage
 	pal1Chars []image.PalettedImage
 	oams      []Oam
+	lcdOn     bool
+	// lines the lcd has been off for, so frames still end while it is
+	lcdOffLines int
+	endOfFrame  bool
+	// how long pixel transfer takes on the current line
+	transferCycles int
+	// the window's own line counter, whether LY has matched WY this frame,
+	// and whether the window covers the next line (after WX=166)
+	windowLine     int
+	wyTriggered    bool
+	windowFullLine bool
+	// the last complete frame, and the one being drawn
+	frame *image.Paletted
+	back  *image.Paletted
 }
 
 var Shade0 = color.RGBA{R: 0x9b, G: 0xbc, B: 0x0f, A: 0xff}
@@ -290,32 +314,6 @@ func renderChars(charCount int, rowsPerChar int, palette color.Palette, paletteM
 	return chars
 }
 
-func (d *Display) Update(cycles uint8) {
-	if d.m.LCDCFlags.IsLCDEnabled() {
-		d.cycles += int(cycles)
-		for d.cycles > CYCLES_PER_LINE {
-			d.m.LY.Write(d.m.LY.Read() + 1)
-			value := d.m.LY.Read()
-			if value >= TOTAL_ROWS {
-				// set it to zero
-				d.m.LY.Write(0)
-			}
-
-			// TODO: should this be ROWS-1?
-			if d.m.LY.Read() == ROWS {
-				// set v-blank flag
-				d.m.StatFlags.SetMode(register.VerticalBlank)
-				d.m.InterruptFlags.VBlankInterrupt()
-			} else {
-				d.m.StatFlags.SetMode(register.EnableCPUAccessToDisplayRAM)
-			}
-
-			// draw a line
-			d.cycles -= CYCLES_PER_LINE
-		}
-	}
-}
-
 func decodeRow(rowData uint16) [8]uint8 {
 	var result [8]uint8
 	for col := 0; col < 8; col++ {
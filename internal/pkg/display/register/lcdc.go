@@ -45,6 +45,16 @@ const (
 	WindowCodeArea2 WindowCodeArea = 2 //0x9C00-0x9FFF
 )
 
+func (a WindowCodeArea) StartAddress() uint16 {
+	if a == WindowCodeArea1 {
+		return 0x9800
+	} else if a == WindowCodeArea2 {
+		return 0x9C00
+	} else {
+		panic("invalid window code area specified!")
+	}
+}
+
 type LCDCFlags struct {
 	flagValues byte
 }
@@ -27,7 +27,8 @@ type StatFlags struct {
 }
 
 func (f *StatFlags) Read() byte {
-	return f.value
+	// bit 7 is unused, and always reads 1
+	return f.value | 0x80
 }
 
 func (f *StatFlags) Write(value byte) {
@@ -49,3 +50,16 @@ func (f *StatFlags) SetMode(mode LcdcMode) {
 func (f *StatFlags) IsInterruptEnabled(selector LcdInterruptSelector) bool {
 	return utils.IsBitSet(f.value, byte(selector))
 }
+
+// IsCoincidence reports whether LY matched LYC when they were last compared.
+func (f *StatFlags) IsCoincidence() bool {
+	return utils.IsBitSet(f.value, 2)
+}
+
+func (f *StatFlags) SetCoincidence(match bool) {
+	if match {
+		f.value = utils.SetBit(f.value, 2)
+	} else {
+		f.value = utils.UnsetBit(f.value, 2)
+	}
+}
@@ -0,0 +1,200 @@
+package display
+
+import "image"
+
+/*
+	Each line is drawn into the framebuffer as pixel transfer finishes, with
+	the scroll, palette and LCDC values in effect at that point - so games
+	that change them mid-frame (split screens, wavy effects) look right.
+
+	The background map is 256x256, and wraps around at its edges.
+
+	The window is drawn over the background from column WX-7, once LY has
+	matched WY at the start of a line this frame - so enabling it part way
+	down a frame works. It has its own line counter, which only moves on
+	lines it's drawn on, so it carries on from where it left off if it's
+	switched off and on again. WX below 7 cuts off the window's left
+	columns. WX=166 draws the window's first pixel in the last column, and
+	(a hardware bug) the whole of the next line.
+
+	Objects are
+	picked from OAM at up to 10 per line. On the DMG the object with the
+	lowest X wins where they overlap, then the one first in OAM. An object
+	with its BG priority bit set is hidden behind background colours 1-3.
+
+	Lines are drawn into a back buffer, which becomes the frame returned by
+	Frame when vblank starts.
+*/
+
+const objsPerLine = 10
+
+func newFrame() *image.Paletted {
+	return image.NewPaletted(image.Rect(0, 0, COLS, ROWS), colors[:])
+}
+
+// Frame is the last complete frame. It's only good until the next one
+// finishes.
+func (d *Display) Frame() *image.Paletted {
+	return d.frame
+}
+
+// finishFrame swaps the back buffer, which has a whole frame drawn, to the
+// front.
+func (d *Display) finishFrame() {
+	d.frame, d.back = d.back, d.frame
+}
+
+// clearFrame blanks the screen, as switching the lcd off does.
+func (d *Display) clearFrame() {
+	for i := range d.frame.Pix {
+		d.frame.Pix[i] = 0
+	}
+}
+
+func (d *Display) renderLine(ly int) {
+	row := d.back.Pix[ly*d.back.Stride : ly*d.back.Stride+COLS]
+	// the background's colour numbers, before the palette - objects need
+	// them for priority
+	var bg [COLS]uint8
+	if d.m.LCDCFlags.IsBgDisplay() {
+		d.renderBackground(ly, &bg)
+		d.renderWindow(&bg)
+		bgp := d.m.BGP.Read()
+		for x := range row {
+			row[x] = paletteShade(bgp, bg[x])
+		}
+	} else {
+		// with the background off, it's blank - whatever the palette
+		for x := range row {
+			row[x] = 0
+		}
+	}
+	if d.m.LCDCFlags.IsObjFlag() {
+		d.renderObjects(ly, row, &bg)
+	}
+}
+
+func paletteShade(palette byte, colour uint8) uint8 {
+	return (palette >> (2 * colour)) & 0x03
+}
+
+func (d *Display) renderBackground(ly int, bg *[COLS]uint8) {
+	mapStart := d.m.LCDCFlags.GetBgCodeArea().StartAddress()
+	y := (ly + int(d.m.SCY.Read())) & 0xFF
+	scx := int(d.m.SCX.Read())
+	d.renderTiles(mapStart, y, scx, 0, bg)
+}
+
+func (d *Display) renderWindow(bg *[COLS]uint8) {
+	fullLine := d.windowFullLine
+	d.windowFullLine = false
+	if !d.m.LCDCFlags.IsWindowingFlagSet() || !d.wyTriggered {
+		return
+	}
+	wx := int(d.m.WX.Read())
+	startX := wx - 7
+	if fullLine {
+		startX = 0
+	} else if startX >= COLS {
+		return
+	} else if startX == COLS-1 {
+		d.windowFullLine = true
+	}
+	mapX := 0
+	if startX < 0 {
+		mapX = -startX
+		startX = 0
+	}
+	mapStart := d.m.LCDCFlags.GetWindowCodeArea().StartAddress()
+	d.renderTiles(mapStart, d.windowLine, mapX, startX, bg)
+	d.windowLine++
+}
+
+// renderTiles draws a row of tiles from a tile map into bg, from screen
+// column startX on. mapX is the map column at startX, and wraps around.
+func (d *Display) renderTiles(mapStart uint16, mapY int, mapX int, startX int, bg *[COLS]uint8) {
+	charArea := d.m.LCDCFlags.GetBgCharArea()
+	mapRow := mapStart + uint16(mapY/8)*32
+	var pixels [8]uint8
+	for x := startX; x < COLS; x++ {
+		px := (mapX + x - startX) & 0xFF
+		if x == startX || px%8 == 0 {
+			tile := d.m.ReadAddr(mapRow + uint16(px/8))
+			pixels = decodeRow(d.m.ReadAddrU16(charArea.Address(tile) + uint16(mapY%8)*2))
+		}
+		bg[x] = pixels[px%8]
+	}
+}
+
+// lineObjs picks the objects on line ly, in OAM order.
+func (d *Display) lineObjs(ly int, height int) []Oam {
+	objs := make([]Oam, 0, objsPerLine)
+	for i := uint16(0); i < 40 && len(objs) < objsPerLine; i++ {
+		addr := 0xFE00 + i*4
+		top := int(d.m.ReadAddr(addr)) - 16
+		if ly < top || ly >= top+height {
+			continue
+		}
+		objs = append(objs, Oam{
+			Y:      d.m.ReadAddr(addr),
+			X:      d.m.ReadAddr(addr + 1),
+			CharID: d.m.ReadAddr(addr + 2),
+			Attrs:  CharAttrs(d.m.ReadAddr(addr + 3)),
+		})
+	}
+	return objs
+}
+
+func (d *Display) renderObjects(ly int, row []uint8, bg *[COLS]uint8) {
+	height := 8
+	if d.m.LCDCFlags.IsDoubleObjTiles() {
+		height = 16
+	}
+	objs := d.lineObjs(ly, height)
+	// which object (if any) has the pixel - the first opaque one by priority
+	var taken [COLS]bool
+	for len(objs) > 0 {
+		best := 0
+		for i, o := range objs {
+			if o.X < objs[best].X {
+				best = i
+			}
+		}
+		o := objs[best]
+		objs = append(objs[:best], objs[best+1:]...)
+
+		tileRow := ly - (int(o.Y) - 16)
+		if o.Attrs.VerticalFlip() {
+			tileRow = height - 1 - tileRow
+		}
+		tile := o.CharID
+		if height == 16 {
+			tile &= 0xFE
+		}
+		pixels := decodeRow(d.m.ReadAddrU16(0x8000 + uint16(tile)*16 + uint16(tileRow)*2))
+		palette := d.m.OBP0.Read()
+		if o.Attrs.IsPal1() {
+			palette = d.m.OBP1.Read()
+		}
+
+		for i := 0; i < 8; i++ {
+			x := int(o.X) - 8 + i
+			if x < 0 || x >= COLS || taken[x] {
+				continue
+			}
+			colour := pixels[i]
+			if o.Attrs.HorizontalFlip() {
+				colour = pixels[7-i]
+			}
+			// colour 0 is transparent
+			if colour == 0 {
+				continue
+			}
+			taken[x] = true
+			if o.Attrs.BgPriority() && bg[x] != 0 {
+				continue
+			}
+			row[x] = paletteShade(palette, colour)
+		}
+	}
+}
@@ -0,0 +1,203 @@
+package display
+
+import (
+	"github.com/mr-tim/goboye/internal/pkg/memory"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+// setTile fills tile id (in 0x8000-0x8FFF) with rows of a single colour.
+func setTile(m *memory.Controller, id byte, colour uint8) {
+	for row := uint16(0); row < 8; row++ {
+		addr := 0x8000 + uint16(id)*16 + row*2
+		m.WriteAddr(addr, 0xFF*(colour&1))
+		m.WriteAddr(addr+1, 0xFF*(colour>>1))
+	}
+}
+
+// setupRenderTest fills the map at 0x9800 with tile 0 (colour 0), except
+// for the last column of the first row, which is tile 1 (colour 3).
+func setupRenderTest() (*memory.Controller, *Display) {
+	m, d := setupDisplayTest()
+	setTile(m, 0, 0)
+	setTile(m, 1, 3)
+	m.WriteAddr(0x9800+31, 1)
+	// identity palette
+	m.WriteAddr(0xFF47, 0xE4)
+	m.WriteAddr(0xFF48, 0xE4)
+	return m, d
+}
+
+func runFrame(m *memory.Controller, d *Display) {
+	advance(m, d, 0)
+	for !d.EndOfFrame() {
+		advance(m, d, CYCLES_PER_LINE)
+	}
+}
+
+func TestRenderBackgroundWraps(t *testing.T) {
+	m, d := setupRenderTest()
+	// scrolled so the screen's left edge is the map's last 4 columns, then
+	// wraps round to its first
+	m.WriteAddr(0xFF43, 0xFC)
+	m.WriteAddr(0xFF42, 0x04)
+	m.WriteAddr(0xFF40, 0x91)
+	runFrame(m, d)
+
+	f := d.Frame()
+	assert.Equal(t, uint8(3), f.ColorIndexAt(0, 0))
+	assert.Equal(t, uint8(3), f.ColorIndexAt(3, 3))
+	assert.Equal(t, uint8(0), f.ColorIndexAt(4, 0))
+	assert.Equal(t, uint8(0), f.ColorIndexAt(0, 4))
+
+	// and from the bottom of the map to the top
+	m.WriteAddr(0xFF42, 0xF4)
+	m.WriteAddr(0xFF43, 0xF8)
+	runFrame(m, d)
+	f = d.Frame()
+	assert.Equal(t, uint8(0), f.ColorIndexAt(0, 11))
+	assert.Equal(t, uint8(3), f.ColorIndexAt(0, 12))
+	assert.Equal(t, uint8(3), f.ColorIndexAt(7, 19))
+	assert.Equal(t, uint8(0), f.ColorIndexAt(0, 20))
+}
+
+func TestRenderUsesValuesAtEachLine(t *testing.T) {
+	m, d := setupRenderTest()
+	m.WriteAddr(0xFF43, 0xF8)
+	m.WriteAddr(0xFF40, 0x91)
+	advance(m, d, 0)
+	// change the palette half way down
+	advance(m, d, CYCLES_PER_LINE*4)
+	m.WriteAddr(0xFF47, 0x1B)
+	runFrame(m, d)
+
+	f := d.Frame()
+	assert.Equal(t, uint8(3), f.ColorIndexAt(0, 3))
+	assert.Equal(t, uint8(0), f.ColorIndexAt(0, 5))
+	assert.Equal(t, uint8(0), f.ColorIndexAt(8, 3))
+	assert.Equal(t, uint8(3), f.ColorIndexAt(8, 5))
+}
+
+func setObj(m *memory.Controller, idx uint16, y, x, tile, attrs byte) {
+	addr := 0xFE00 + idx*4
+	m.WriteAddr(addr, y)
+	m.WriteAddr(addr+1, x)
+	m.WriteAddr(addr+2, tile)
+	m.WriteAddr(addr+3, attrs)
+}
+
+func TestRenderObjects(t *testing.T) {
+	m, d := setupRenderTest()
+	setTile(m, 2, 1)
+	setTile(m, 3, 2)
+	// overlapping - the lower X wins, though it's later in OAM
+	setObj(m, 0, 16, 12, 2, 0x00)
+	setObj(m, 1, 16, 8, 3, 0x00)
+	// behind the background where it isn't colour 0
+	setObj(m, 2, 16+8, 8, 2, 0x80)
+	m.WriteAddr(0x9800+32, 1)
+	m.WriteAddr(0xFF40, 0x93)
+	runFrame(m, d)
+
+	f := d.Frame()
+	assert.Equal(t, uint8(2), f.ColorIndexAt(0, 0))
+	assert.Equal(t, uint8(2), f.ColorIndexAt(7, 0))
+	assert.Equal(t, uint8(1), f.ColorIndexAt(8, 0))
+	assert.Equal(t, uint8(0), f.ColorIndexAt(12, 0))
+	assert.Equal(t, uint8(3), f.ColorIndexAt(0, 8))
+}
+
+func TestRenderTenObjectsPerLine(t *testing.T) {
+	m, d := setupRenderTest()
+	setTile(m, 2, 1)
+	for i := uint16(0); i < 12; i++ {
+		setObj(m, i, 16, byte(8+i*8), 2, 0x00)
+	}
+	m.WriteAddr(0xFF40, 0x93)
+	runFrame(m, d)
+
+	f := d.Frame()
+	assert.Equal(t, uint8(1), f.ColorIndexAt(72, 0))
+	assert.Equal(t, uint8(0), f.ColorIndexAt(80, 0))
+}
+
+// setupWindowTest has the window map at 0x9C00 with rows of tiles 1, 2 and 3
+// (colours 3, 1 and 2).
+func setupWindowTest() (*memory.Controller, *Display) {
+	m, d := setupRenderTest()
+	setTile(m, 2, 1)
+	setTile(m, 3, 2)
+	for i := uint16(0); i < 32; i++ {
+		m.WriteAddr(0x9C00+i, 1)
+		m.WriteAddr(0x9C20+i, 2)
+		m.WriteAddr(0x9C40+i, 3)
+	}
+	return m, d
+}
+
+func TestRenderWindow(t *testing.T) {
+	m, d := setupWindowTest()
+	m.WriteAddr(0xFF4A, 10)
+	m.WriteAddr(0xFF4B, 7+80)
+	m.WriteAddr(0xFF40, 0xF1)
+	runFrame(m, d)
+
+	f := d.Frame()
+	assert.Equal(t, uint8(0), f.ColorIndexAt(100, 9))
+	assert.Equal(t, uint8(0), f.ColorIndexAt(79, 10))
+	assert.Equal(t, uint8(3), f.ColorIndexAt(80, 10))
+	assert.Equal(t, uint8(3), f.ColorIndexAt(159, 17))
+	assert.Equal(t, uint8(1), f.ColorIndexAt(80, 18))
+}
+
+func TestWindowLineCounter(t *testing.T) {
+	m, d := setupWindowTest()
+	m.WriteAddr(0xFF4A, 0)
+	m.WriteAddr(0xFF4B, 7)
+	m.WriteAddr(0xFF40, 0xD1)
+	advance(m, d, 0)
+	// enabled from line 20 - it starts from its own first line
+	advance(m, d, CYCLES_PER_LINE*20)
+	m.WriteAddr(0xFF40, 0xF1)
+	// and off for lines 30-39, so it picks up again from its line 10
+	advance(m, d, CYCLES_PER_LINE*10)
+	m.WriteAddr(0xFF40, 0xD1)
+	advance(m, d, CYCLES_PER_LINE*10)
+	m.WriteAddr(0xFF40, 0xF1)
+	runFrame(m, d)
+
+	f := d.Frame()
+	assert.Equal(t, uint8(0), f.ColorIndexAt(0, 19))
+	assert.Equal(t, uint8(3), f.ColorIndexAt(0, 20))
+	assert.Equal(t, uint8(1), f.ColorIndexAt(0, 28))
+	assert.Equal(t, uint8(0), f.ColorIndexAt(0, 35))
+	assert.Equal(t, uint8(1), f.ColorIndexAt(0, 40))
+	assert.Equal(t, uint8(2), f.ColorIndexAt(0, 46))
+}
+
+func TestWindowXQuirks(t *testing.T) {
+	m, d := setupWindowTest()
+	m.WriteAddr(0x9C00, 2)
+	m.WriteAddr(0xFF4A, 0)
+	// WX below 7 cuts off the left of the window
+	m.WriteAddr(0xFF4B, 3)
+	m.WriteAddr(0xFF40, 0xF1)
+	advance(m, d, 0)
+	advance(m, d, CYCLES_PER_LINE)
+	// WX=166 is just the last column, then all of the next line
+	m.WriteAddr(0xFF4B, 166)
+	advance(m, d, CYCLES_PER_LINE*3)
+	m.WriteAddr(0xFF4B, 200)
+	runFrame(m, d)
+
+	f := d.Frame()
+	assert.Equal(t, uint8(1), f.ColorIndexAt(3, 0))
+	assert.Equal(t, uint8(3), f.ColorIndexAt(4, 0))
+	assert.Equal(t, uint8(0), f.ColorIndexAt(158, 1))
+	assert.Equal(t, uint8(1), f.ColorIndexAt(159, 1))
+	assert.Equal(t, uint8(1), f.ColorIndexAt(0, 2))
+	assert.Equal(t, uint8(3), f.ColorIndexAt(8, 2))
+	assert.Equal(t, uint8(1), f.ColorIndexAt(159, 3))
+	assert.Equal(t, uint8(3), f.ColorIndexAt(8, 4))
+	assert.Equal(t, uint8(0), f.ColorIndexAt(8, 5))
+}
@@ -0,0 +1,122 @@
+package display
+
+import (
+	"github.com/mr-tim/goboye/internal/pkg/display/register"
+	"github.com/mr-tim/goboye/internal/pkg/scheduler"
+)
+
+/*
+	Each of the 154 lines takes 456 cycles. The 144 visible ones go through:
+
+		mode 2 - OAM search, 80 cycles
+		mode 3 - pixel transfer, 172 cycles plus SCX mod 8, as the first
+		         tile's hidden pixels are fetched and thrown away. The line
+		         is drawn as it ends.
+		mode 0 - hblank, the rest of the line
+
+	then lines 144-153 are all mode 1 - vblank, which requests the VBlank
+	interrupt as it starts. LY is the current line.
+
+	Each mode change is a scheduled event. Switching the lcd off stops all
+	this - LY is held at 0 in mode 0 - and switching it on starts again at
+	line 0.
+*/
+
+// HandleEvent runs the display's events - the change to the next mode, or
+// the lcd being switched on or off.
+func (d *Display) HandleEvent(event scheduler.Event) {
+	if event != scheduler.DisplayMode {
+		return
+	}
+	enabled := d.m.LCDCFlags.IsLCDEnabled()
+	if enabled && !d.lcdOn {
+		d.lcdOn = true
+		d.startLine(0)
+	} else if !enabled && d.lcdOn {
+		d.lcdOn = false
+		d.switchOff()
+	} else if !enabled {
+		d.lcdOffLine()
+	} else {
+		d.nextMode()
+	}
+	d.m.UpdateStatInterrupt()
+}
+
+// schedule the next mode change. The display runs at the same rate in double
+// speed, so it takes twice as many cpu cycles.
+func (d *Display) schedule(cycles int) {
+	if d.m.SpeedSwitch.IsDoubleSpeed() {
+		cycles *= 2
+	}
+	d.m.Scheduler().Schedule(scheduler.DisplayMode, uint64(cycles))
+}
+
+func (d *Display) nextMode() {
+	switch d.m.StatFlags.GetMode() {
+	case register.SearchingOAMRAM:
+		d.m.StatFlags.SetMode(register.TransferringDataToLCDDriver)
+		d.transferCycles = TRANSFER_CYCLES + int(d.m.SCX.Read()&0x07)
+		d.schedule(d.transferCycles)
+	case register.TransferringDataToLCDDriver:
+		d.renderLine(int(d.m.LY.Read()))
+		d.m.StatFlags.SetMode(register.EnableCPUAccessToDisplayRAM)
+		d.schedule(CYCLES_PER_LINE - OAM_SEARCH_CYCLES - d.transferCycles)
+	default:
+		d.startLine(int(d.m.LY.Read()) + 1)
+	}
+}
+
+func (d *Display) startLine(line int) {
+	if line >= TOTAL_ROWS {
+		line = 0
+	}
+	d.m.LY.Write(byte(line))
+	if line == 0 {
+		d.windowLine = 0
+		d.wyTriggered = false
+		d.windowFullLine = false
+	}
+	if line < ROWS {
+		if line == int(d.m.WY.Read()) {
+			d.wyTriggered = true
+		}
+		d.m.StatFlags.SetMode(register.SearchingOAMRAM)
+		d.schedule(OAM_SEARCH_CYCLES)
+		return
+	}
+	if line == ROWS {
+		d.m.StatFlags.SetMode(register.VerticalBlank)
+		d.m.InterruptFlags.VBlankInterrupt()
+		d.finishFrame()
+		d.endOfFrame = true
+	}
+	d.schedule(CYCLES_PER_LINE)
+}
+
+func (d *Display) switchOff() {
+	d.m.LY.Write(0)
+	d.m.StatFlags.SetMode(register.EnableCPUAccessToDisplayRAM)
+	d.clearFrame()
+	d.lcdOffLines = 0
+	d.schedule(CYCLES_PER_LINE)
+}
+
+// lcdOffLine keeps time while the lcd is off, so frames still end.
+func (d *Display) lcdOffLine() {
+	d.lcdOffLines += 1
+	if d.lcdOffLines >= TOTAL_ROWS {
+		d.lcdOffLines = 0
+		d.endOfFrame = true
+	}
+	d.schedule(CYCLES_PER_LINE)
+}
+
+// EndOfFrame reports whether a frame has finished (the display has entered
+// vblank, or a frame's worth of lines has passed with the lcd off) since it
+// was last called.
+func (d *Display) EndOfFrame() bool {
+	ended := d.endOfFrame
+	d.endOfFrame = false
+	return ended
+}
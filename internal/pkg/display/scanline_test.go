@@ -0,0 +1,143 @@
+package display
+
+import (
+	"github.com/mr-tim/goboye/internal/pkg/display/register"
+	"github.com/mr-tim/goboye/internal/pkg/memory"
+	"github.com/mr-tim/goboye/internal/pkg/scheduler"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+// displayOnly runs just the display's events.
+type displayOnly struct {
+	d *Display
+}
+
+func (h displayOnly) HandleEvent(event scheduler.Event) {
+	h.d.HandleEvent(event)
+}
+
+func setupDisplayTest() (*memory.Controller, *Display) {
+	m := memory.NewController()
+	d := NewDisplay(&m)
+	return &m, &d
+}
+
+func advance(m *memory.Controller, d *Display, cycles uint64) {
+	m.Scheduler().Advance(cycles, displayOnly{d})
+}
+
+func TestLineModes(t *testing.T) {
+	m, d := setupDisplayTest()
+	m.WriteAddr(0xFF43, 0x03)
+	m.WriteAddr(0xFF40, 0x80)
+	advance(m, d, 0)
+
+	assert.Equal(t, register.SearchingOAMRAM, m.StatFlags.GetMode())
+	advance(m, d, OAM_SEARCH_CYCLES)
+	assert.Equal(t, register.TransferringDataToLCDDriver, m.StatFlags.GetMode())
+	// SCX mod 8 extends pixel transfer
+	advance(m, d, TRANSFER_CYCLES+2)
+	assert.Equal(t, register.TransferringDataToLCDDriver, m.StatFlags.GetMode())
+	advance(m, d, 1)
+	assert.Equal(t, register.EnableCPUAccessToDisplayRAM, m.StatFlags.GetMode())
+	assert.Equal(t, uint8(0), m.ReadAddr(0xFF44))
+
+	advance(m, d, CYCLES_PER_LINE-OAM_SEARCH_CYCLES-TRANSFER_CYCLES-3)
+	assert.Equal(t, register.SearchingOAMRAM, m.StatFlags.GetMode())
+	assert.Equal(t, uint8(1), m.ReadAddr(0xFF44))
+}
+
+func TestEndOfFrame(t *testing.T) {
+	m, d := setupDisplayTest()
+	m.WriteAddr(0xFF40, 0x80)
+
+	advance(m, d, CYCLES_PER_LINE*ROWS-1)
+	assert.False(t, d.EndOfFrame())
+	advance(m, d, 1)
+	assert.True(t, d.EndOfFrame())
+	assert.True(t, m.InterruptFlags.VBlank())
+	assert.Equal(t, register.VerticalBlank, m.StatFlags.GetMode())
+	assert.False(t, d.EndOfFrame())
+
+	advance(m, d, CYCLES_PER_LINE*VBLANK_ROWS)
+	assert.Equal(t, uint8(0), m.ReadAddr(0xFF44))
+	assert.Equal(t, register.SearchingOAMRAM, m.StatFlags.GetMode())
+
+	// switching off holds LY at 0, but frames still end
+	advance(m, d, CYCLES_PER_LINE*3)
+	m.WriteAddr(0xFF40, 0x00)
+	advance(m, d, 0)
+	assert.Equal(t, uint8(0), m.ReadAddr(0xFF44))
+	assert.Equal(t, register.EnableCPUAccessToDisplayRAM, m.StatFlags.GetMode())
+	advance(m, d, CYCLES_PER_FRAME)
+	assert.True(t, d.EndOfFrame())
+}
+
+func TestSpeedSwitchMidLine(t *testing.T) {
+	m, d := setupDisplayTest()
+	m.SetModel(memory.CGB)
+	m.WriteAddr(0xFF40, 0x80)
+	advance(m, d, 0)
+
+	// switching halfway through OAM search leaves 40 display cycles, which
+	// take 80 cpu cycles in double speed
+	advance(m, d, OAM_SEARCH_CYCLES/2)
+	m.SwitchSpeed()
+	advance(m, d, OAM_SEARCH_CYCLES-1)
+	assert.Equal(t, register.SearchingOAMRAM, m.StatFlags.GetMode())
+	advance(m, d, 1)
+	assert.Equal(t, register.TransferringDataToLCDDriver, m.StatFlags.GetMode())
+
+	// and back again, halfway through pixel transfer
+	advance(m, d, TRANSFER_CYCLES)
+	m.SwitchSpeed()
+	advance(m, d, TRANSFER_CYCLES/2-1)
+	assert.Equal(t, register.TransferringDataToLCDDriver, m.StatFlags.GetMode())
+	advance(m, d, 1)
+	assert.Equal(t, register.EnableCPUAccessToDisplayRAM, m.StatFlags.GetMode())
+}
+
+func TestLycInterrupt(t *testing.T) {
+	m, d := setupDisplayTest()
+	m.WriteAddr(0xFF45, 2)
+	m.WriteAddr(0xFF41, 0x40)
+	m.WriteAddr(0xFF40, 0x80)
+
+	advance(m, d, CYCLES_PER_LINE*2-1)
+	assert.False(t, m.InterruptFlags.LcdStatus())
+	assert.False(t, m.StatFlags.IsCoincidence())
+	advance(m, d, 1)
+	assert.True(t, m.InterruptFlags.LcdStatus())
+	assert.Equal(t, uint8(0xC6), m.ReadAddr(0xFF41))
+
+	// changing LYC to the current line counts too
+	m.InterruptFlags.Write(0x00)
+	advance(m, d, CYCLES_PER_LINE)
+	assert.False(t, m.InterruptFlags.LcdStatus())
+	m.WriteAddr(0xFF45, 3)
+	assert.True(t, m.InterruptFlags.LcdStatus())
+}
+
+func TestStatBlocking(t *testing.T) {
+	m, d := setupDisplayTest()
+	// hblank and LY=LYC, with LYC matching the line after the hblank
+	m.WriteAddr(0xFF45, 1)
+	m.WriteAddr(0xFF41, 0x48)
+	m.WriteAddr(0xFF40, 0x80)
+
+	advance(m, d, CYCLES_PER_LINE-1)
+	assert.True(t, m.InterruptFlags.LcdStatus())
+	m.InterruptFlags.Write(0x00)
+
+	// LY=LYC rises just as hblank ends, so the line never drops - there's no
+	// new interrupt for the whole of line 1
+	advance(m, d, 1)
+	assert.False(t, m.InterruptFlags.LcdStatus())
+	advance(m, d, CYCLES_PER_LINE-1)
+	assert.False(t, m.InterruptFlags.LcdStatus())
+
+	// line 2 drops both, then its hblank raises the line again
+	advance(m, d, CYCLES_PER_LINE)
+	assert.True(t, m.InterruptFlags.LcdStatus())
+}
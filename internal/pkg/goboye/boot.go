@@ -0,0 +1,55 @@
+package goboye
+
+import (
+	"github.com/mr-tim/goboye/internal/pkg/memory"
+	"os"
+)
+
+// SetModel picks the hardware to emulate. Like the other boot settings, it
+// takes effect when the next rom is loaded.
+func (e *Emulator) SetModel(model memory.Model) {
+	e.model = model
+}
+
+// LoadBootRomImage runs the boot rom from a file instead of the built in DMG
+// one. A CGB sized boot rom also switches the model to CGB.
+func (e *Emulator) LoadBootRomImage(filename string) error {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return err
+	}
+	if err = memory.CheckBootRom(data); err != nil {
+		return err
+	}
+	e.bootRom = data
+	if len(data) == memory.CGB.BootRomSize() {
+		e.model = memory.CGB
+	}
+	return nil
+}
+
+// SetSkipBoot starts roms straight at 0x0100, with the registers set as the
+// boot rom would have left them.
+func (e *Emulator) SetSkipBoot(skip bool) {
+	e.skipBoot = skip
+}
+
+// ConfigureBoot applies the boot options shared by the commands: a boot rom
+// file and model name (either may be empty, for the defaults) and whether to
+// skip the boot rom.
+func (e *Emulator) ConfigureBoot(bootRomFile string, modelName string, skip bool) error {
+	if bootRomFile != "" {
+		if err := e.LoadBootRomImage(bootRomFile); err != nil {
+			return err
+		}
+	}
+	if modelName != "" {
+		model, err := memory.ParseModel(modelName)
+		if err != nil {
+			return err
+		}
+		e.SetModel(model)
+	}
+	e.SetSkipBoot(skip)
+	return nil
+}
@@ -0,0 +1,86 @@
+package goboye
+
+import (
+	"bytes"
+	"errors"
+	"github.com/mr-tim/goboye/internal/pkg/cheats"
+	"log"
+	"os"
+)
+
+func (e *Emulator) loadCheatFile() {
+	f, err := os.Open(e.cheatPath)
+	if err != nil {
+		if !errors.Is(err, os.ErrNotExist) {
+			log.Printf("Unable to read cheat file %s: %s", e.cheatPath, err)
+		}
+		return
+	}
+	defer f.Close()
+
+	if err = e.cheats.Read(f); err != nil {
+		log.Printf("Ignoring rest of cheat file %s: %s", e.cheatPath, err)
+	}
+	log.Printf("Loaded %d cheats from %s", len(e.cheats.Cheats()), e.cheatPath)
+	e.updateCheatHooks()
+}
+
+func (e *Emulator) saveCheatFile() error {
+	if e.cheatPath == "" {
+		return nil
+	}
+	b := new(bytes.Buffer)
+	if err := e.cheats.Write(b); err != nil {
+		return err
+	}
+	return writeFileAtomic(e.cheatPath, b.Bytes())
+}
+
+// updateCheatHooks only hooks rom reads while there are Game Genie codes, so
+// rom reads stay fast without them. There's nothing to hook until a rom is
+// loaded.
+func (e *Emulator) updateCheatHooks() {
+	if e.memory == nil {
+		return
+	}
+	if e.cheats.HasRomCheats() {
+		e.memory.SetRomReadHook(e.cheats)
+	} else {
+		e.memory.SetRomReadHook(nil)
+	}
+}
+
+func (e *Emulator) applyRamCheats() {
+	e.cheats.ApplyRamWrites(e.memory)
+}
+
+// AddCheat adds a Game Genie or GameShark code, enabled, and saves it to the
+// rom's cheat file.
+func (e *Emulator) AddCheat(code string, name string) (cheats.Cheat, error) {
+	c, err := e.cheats.Add(code, name)
+	if err != nil {
+		return c, err
+	}
+	e.updateCheatHooks()
+	return c, e.saveCheatFile()
+}
+
+func (e *Emulator) RemoveCheat(code string) error {
+	if err := e.cheats.Remove(code); err != nil {
+		return err
+	}
+	e.updateCheatHooks()
+	return e.saveCheatFile()
+}
+
+func (e *Emulator) SetCheatEnabled(code string, enabled bool) error {
+	if err := e.cheats.SetEnabled(code, enabled); err != nil {
+		return err
+	}
+	e.updateCheatHooks()
+	return e.saveCheatFile()
+}
+
+func (e *Emulator) Cheats() []cheats.Cheat {
+	return e.cheats.Cheats()
+}
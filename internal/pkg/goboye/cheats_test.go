@@ -0,0 +1,15 @@
+package goboye
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestCheatsBeforeRomIsLoaded(t *testing.T) {
+	e := NewEmulator()
+	_, err := e.AddCheat("3E1-5AF-E6A", "")
+	assert.Nil(t, err)
+	assert.Nil(t, e.SetCheatEnabled("3E1-5AF-E6A", false))
+	assert.Nil(t, e.RemoveCheat("3E1-5AF-E6A"))
+	assert.Empty(t, e.Cheats())
+}
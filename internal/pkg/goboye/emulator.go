@@ -6,23 +6,43 @@ import (
 	"encoding/hex"
 	"errors"
 	"fmt"
+	"github.com/mr-tim/goboye/internal/pkg/cheats"
 	"github.com/mr-tim/goboye/internal/pkg/cpu"
 	"github.com/mr-tim/goboye/internal/pkg/debugger/recorder"
+	"github.com/mr-tim/goboye/internal/pkg/debugger/trace"
 	"github.com/mr-tim/goboye/internal/pkg/display"
 	"github.com/mr-tim/goboye/internal/pkg/goboye/button"
 	"github.com/mr-tim/goboye/internal/pkg/memory"
+	"github.com/mr-tim/goboye/internal/pkg/romfile"
+	"github.com/mr-tim/goboye/internal/pkg/scheduler"
 	"image"
+	"io"
 	"log"
 	"os"
 )
 
 type Emulator struct {
-	memory      *memory.Controller
-	processor   cpu.Processor
-	display     display.Display
-	breakpoints [0xFFFF]bool
-	recorder    *recorder.Recorder
-	debug       bool
+	memory        *memory.Controller
+	processor     cpu.Processor
+	display       display.Display
+	breakpoints   [0xFFFF]bool
+	recorder      *recorder.Recorder
+	trace         *trace.Writer
+	debug         bool
+	cycleClock    *memory.CycleClock
+	savePath      string
+	saveDirty     bool
+	cheats        *cheats.Engine
+	cheatPath     string
+	ramSearch     *cheats.Search
+	model         memory.Model
+	bootRom       []byte
+	skipBoot      bool
+	cycleAccurate bool
+	decodeCache   bool
+	doctor        bool
+
+	framesSinceFlush int
 }
 
 func NewEmulator() *Emulator {
@@ -30,6 +50,7 @@ func NewEmulator() *Emulator {
 	return &(Emulator{
 		breakpoints: breakpoints,
 		recorder:    recorder.NewRecorder(),
+		cheats:      cheats.NewEngine(),
 	})
 }
 
@@ -59,18 +80,85 @@ func loadBreakpoints() [0xFFFF]bool {
 	return breakpoints
 }
 
+// LoadRomImage loads a rom file, which may be inside a zip or gzip archive
+// (see romfile.Open). Battery backed saves are kept in a .sav file next to it.
+// A patch with the same name as the rom (eg. game.ips for game.gb) is applied
+// if there is one.
 func (e *Emulator) LoadRomImage(filename string) {
-	m := memory.NewController()
-	e.memory = &m
+	e.LoadPatchedRomImage(filename, "")
+}
 
+// LoadPatchedRomImage is LoadRomImage with an IPS, UPS or BPS patch applied
+// to the rom. If patchFile is empty, a patch next to the rom is looked for.
+func (e *Emulator) LoadPatchedRomImage(filename string, patchFile string) {
 	log.Printf("Loading rom: %s", filename)
-	err := e.memory.LoadRomImage(filename)
+	rom, err := romfile.Open(filename)
 	if err != nil {
 		panic(err)
 	}
+	if patchFile == "" {
+		patchFile = findPatchForRom(filename)
+	}
+	if patchFile != "" {
+		log.Printf("Applying patch: %s", patchFile)
+		if rom, err = applyPatchFile(rom, patchFile); err != nil {
+			panic(err)
+		}
+	}
+	if err = e.loadRom(rom); err != nil {
+		panic(err)
+	}
+
+	e.savePath = savePathForRom(filename)
+	e.loadSaveFile()
+	e.cheatPath = romSiblingPath(filename, ".cht")
+	e.loadCheatFile()
+}
+
+// LoadRom loads a rom, or an archive containing one, from r. Roms loaded this
+// way have no save file - use ImportSaveRam and ExportSaveRam instead.
+func (e *Emulator) LoadRom(r io.Reader) error {
+	rom, err := romfile.Read(r, "")
+	if err != nil {
+		return err
+	}
+	return e.loadRom(rom)
+}
 
-	e.processor = cpu.NewProcessor(e.memory)
+func (e *Emulator) loadRom(rom []byte) error {
+	m := memory.NewController()
+	m.SetModel(e.model)
+	m.SetDoctorLy(e.doctor)
+	if e.cycleClock != nil {
+		m.SetRtcClock(e.cycleClock)
+	}
+	if e.bootRom != nil {
+		if err := m.LoadBootRom(e.bootRom); err != nil {
+			return err
+		}
+	}
+	if err := m.LoadRom(rom); err != nil {
+		return err
+	}
+	e.memory = &m
+	e.savePath = ""
+	e.saveDirty = false
+	e.cheats = cheats.NewEngine()
+	e.cheatPath = ""
+	e.ramSearch = nil
+
+	if e.skipBoot {
+		e.memory.SkipBootRom()
+		e.processor = cpu.NewProcessorAfterBoot(e.memory)
+	} else {
+		e.processor = cpu.NewProcessor(e.memory)
+	}
+	if e.cycleAccurate {
+		e.processor.SetTicker(systemTicker{e})
+	}
+	e.processor.SetDecodeCache(e.decodeCache)
 	e.display = display.NewDisplay(e.memory)
+	return nil
 }
 
 func (e *Emulator) GetDisassembler() cpu.Disassembler {
@@ -85,33 +173,87 @@ func (e *Emulator) GetRegisterPair(registerPair cpu.RegisterPair) uint16 {
 	return e.processor.GetRegisterPair(registerPair)
 }
 
+func (e *Emulator) GetRomBank() int {
+	return e.memory.RomBank()
+}
+
 func (e *Emulator) GetFlagValue(flagName cpu.OpResultFlag) bool {
 	return e.processor.GetFlagValue(flagName)
 }
 
 func (e *Emulator) Step() uint8 {
-	if e.processor.IsStopped() || e.processor.IsHalted() {
-		return 0
+	// a stopped cpu only checks whether a button has woken it
+	wasIdle := e.processor.IsHalted() || e.processor.IsStopped()
+	if !wasIdle {
+		e.recorder.TakeSnapshot(e.processor, e.memory)
+		e.writeTrace()
 	}
-	e.recorder.TakeSnapshot(e.processor, e.memory)
 	pc := e.GetPC()
+	_, wasLocked := e.processor.Lockup()
 	c := e.processor.DoNextInstruction()
+	if lockup, locked := e.processor.Lockup(); locked && !wasLocked {
+		log.Printf("CPU locked up: %s", lockup)
+	}
 	// break on infinite loops (PC isn't advancing because of JrN -1
-	if pc == e.GetPC() {
+	if pc == e.GetPC() && !wasIdle {
 		// infinite loop
 		e.breakpoints[e.GetPC()] = true
 	}
-	e.display.Update(c)
+	if !e.cycleAccurate {
+		e.advance(c)
+	}
 	return c
 }
 
+func (e *Emulator) writeTrace() {
+	if e.trace == nil {
+		return
+	}
+	if _, locked := e.processor.Lockup(); locked {
+		return
+	}
+	if err := e.trace.Write(e.processor, e.memory); err != nil {
+		log.Printf("Unable to write trace, stopping it: %s", err)
+		e.trace = nil
+	}
+}
+
+// SetTrace writes a Gameboy Doctor style trace of every instruction run to
+// w, or stops tracing if w is nil. The trace is flushed by Close.
+func (e *Emulator) SetTrace(w io.Writer) {
+	if w == nil {
+		e.trace = nil
+	} else {
+		e.trace = trace.NewWriter(w)
+	}
+}
+
+// SetTraceWriter traces to a writer that may be shared with other emulators,
+// or stops tracing if t is nil. Close flushes it.
+func (e *Emulator) SetTraceWriter(t *trace.Writer) {
+	e.trace = t
+}
+
+// displayCycles converts cpu cycles to the display's clock, which (like real
+// time) doesn't speed up when the CGB is in double speed mode.
+func (e *Emulator) displayCycles(cycles uint8) uint8 {
+	if e.processor.SpeedMode() == cpu.DoubleSpeed {
+		return cycles / 2
+	}
+	return cycles
+}
+
 func (e *Emulator) StepFrame() {
 	e.ContinueDebugging(true)
+	// GameShark codes are written at the end of each frame, during vblank
+	e.applyRamCheats()
+	e.periodicFlush()
 }
 
 func (e *Emulator) ContinueDebugging(stopOnFrame bool) {
 	stepCount := 0
-	cycleCount := 0
+	// only stop at the end of a frame that finishes from here on
+	e.display.EndOfFrame()
 
 	if e.debug {
 		defer func() {
@@ -129,11 +271,20 @@ func (e *Emulator) ContinueDebugging(stopOnFrame bool) {
 	}
 
 	for {
-		cycles := e.Step()
-		e.updateTimers(cycles)
-		cycleCount += int(cycles)
+		e.Step()
+
+		if e.processor.IsStopped() {
+			break
+		}
 
-		if e.processor.IsHalted() || e.processor.IsStopped() {
+		// nothing more will run, so there's no point continuing
+		if _, locked := e.processor.Lockup(); locked && !stopOnFrame {
+			break
+		}
+
+		// with no interrupts enabled, nothing can wake a halted cpu
+		if e.processor.IsHalted() && !stopOnFrame && e.memory.InterruptEnabled.Read()&0x1F == 0 {
+			fmt.Printf("Halted at %04X with no interrupts enabled\n", e.GetPC())
 			break
 		}
 
@@ -146,29 +297,48 @@ func (e *Emulator) ContinueDebugging(stopOnFrame bool) {
 			}
 		}
 
-		if stopOnFrame {
-			if e.memory.LCDCFlags.IsLCDEnabled() {
-				if cycleCount >= display.CYCLES_PER_FRAME && e.memory.LYC.Read() == 0 {
-					break
-				}
-			}
+		if stopOnFrame && e.display.EndOfFrame() {
+			break
 		}
 		stepCount += 1
 	}
 }
 
-func (e *Emulator) updateTimers(cycles uint8) {
-	e.memory.Divider.Update(cycles)
-	if e.memory.TimerController.IsStarted() {
-		if e.memory.TimerController.UpdateCountdown(cycles) {
-			if e.memory.TimerCounter.Read() == 255 {
-				e.memory.TimerCounter.Write(e.memory.TimerModulo.Read())
-				e.memory.InterruptFlags.TimerOverflowInterrupt()
-			} else {
-				e.memory.TimerCounter.Write(e.memory.TimerCounter.Read() + 1)
-			}
-		}
+// advance moves the master clock on, running any events that fall due.
+func (e *Emulator) advance(cycles uint8) {
+	if e.cycleClock != nil {
+		e.cycleClock.Advance(e.displayCycles(cycles))
 	}
+	e.memory.Scheduler().Advance(uint64(cycles), systemTicker{e})
+}
+
+// systemTicker advances the clock alongside a cycle accurate cpu, and hands
+// scheduled events to the hardware they belong to.
+type systemTicker struct {
+	e *Emulator
+}
+
+func (t systemTicker) Tick(cycles uint8) {
+	t.e.advance(cycles)
+}
+
+func (t systemTicker) HandleEvent(event scheduler.Event) {
+	if event == scheduler.DisplayMode {
+		t.e.display.HandleEvent(event)
+	} else {
+		t.e.memory.HandleEvent(event)
+	}
+}
+
+// Clock is the master clock - cpu cycles since the rom was loaded.
+func (e *Emulator) Clock() uint64 {
+	return e.memory.Scheduler().Now()
+}
+
+// Lockup reports the illegal opcode that hung the cpu, if it has run one.
+// ContinueDebugging stops when it happens.
+func (e *Emulator) Lockup() (cpu.IllegalOpcode, bool) {
+	return e.processor.Lockup()
 }
 
 func (e *Emulator) AddBreakpoint(addr uint16) {
@@ -198,6 +368,13 @@ func (e *Emulator) DebugRender() image.Image {
 	return e.display.DebugRenderMemory()
 }
 
+// Frame is the last frame the display finished - 160x144, with the palette
+// indexes being shades 0 (lightest) to 3. It's only good until the next
+// frame finishes, so copy it to keep it.
+func (e *Emulator) Frame() *image.Paletted {
+	return e.display.Frame()
+}
+
 func (e *Emulator) SerialOutput() string {
 	return e.memory.SerialOutput
 }
@@ -206,6 +383,44 @@ func (e *Emulator) SetDebug(debug bool) {
 	e.debug = debug
 }
 
+// SetRtcFromCycles runs the cartridge real-time clock from emulated cycles
+// rather than host time, so runs are repeatable.
+func (e *Emulator) SetRtcFromCycles(fromCycles bool) {
+	if fromCycles {
+		e.cycleClock = memory.NewCycleClock()
+		if e.memory != nil {
+			e.memory.SetRtcClock(e.cycleClock)
+		}
+	} else {
+		e.cycleClock = nil
+		if e.memory != nil {
+			e.memory.SetRtcClock(memory.NewHostClock())
+		}
+	}
+}
+
+// SetCycleAccurate runs the timers and display on every memory access the cpu
+// makes, instead of once per instruction. It's slower, but some games and
+// test roms rely on the exact timing. It takes effect when the next rom is
+// loaded.
+func (e *Emulator) SetCycleAccurate(cycleAccurate bool) {
+	e.cycleAccurate = cycleAccurate
+}
+
+// SetDecodeCache keeps instructions decoded from rom, rather than decoding
+// them each time they run. It takes effect when the next rom is loaded.
+func (e *Emulator) SetDecodeCache(enabled bool) {
+	e.decodeCache = enabled
+}
+
+// SetDoctor makes LY always read 0x90, as Gameboy Doctor's reference logs
+// assume, so a trace can be compared with them. Games that wait for a
+// particular line won't run properly. It takes effect when the next rom is
+// loaded.
+func (e *Emulator) SetDoctor(enabled bool) {
+	e.doctor = enabled
+}
+
 func (e *Emulator) SetButtonState(button button.Button, isDown bool) {
 	e.memory.ControllerData.SetButtonState(button, isDown)
 }
@@ -0,0 +1,33 @@
+package goboye
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestContinueStopsAtHaltThatCantWake(t *testing.T) {
+	rom := make([]byte, 0x8000)
+	copy(rom[0x0100:], []byte{
+		0xF3,       // DI
+		0xAF,       // XOR A
+		0xE0, 0xFF, // LDH (0xFF),A
+		0x76, // HALT
+	})
+	e := NewEmulator()
+	e.SetSkipBoot(true)
+	assert.Nil(t, e.loadRom(rom))
+
+	done := make(chan bool)
+	go func() {
+		e.ContinueDebugging(false)
+		done <- true
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("still running after halting with IE=0")
+	}
+	assert.True(t, e.processor.IsHalted())
+	assert.Equal(t, uint16(0x0105), e.GetPC())
+}
@@ -0,0 +1,31 @@
+package goboye
+
+import (
+	"fmt"
+	"github.com/mr-tim/goboye/internal/pkg/patch"
+	"os"
+)
+
+// findPatchForRom looks for a patch named after the rom, eg. game.ips for
+// game.gb. It returns an empty string if there isn't one.
+func findPatchForRom(romPath string) string {
+	for _, ext := range patch.Extensions {
+		p := romSiblingPath(romPath, ext)
+		if _, err := os.Stat(p); err == nil {
+			return p
+		}
+	}
+	return ""
+}
+
+func applyPatchFile(rom []byte, patchFile string) ([]byte, error) {
+	p, err := os.ReadFile(patchFile)
+	if err != nil {
+		return nil, err
+	}
+	patched, err := patch.Apply(rom, p)
+	if err != nil {
+		return nil, fmt.Errorf("unable to apply patch %s: %w", patchFile, err)
+	}
+	return patched, nil
+}
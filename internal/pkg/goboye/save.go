@@ -0,0 +1,127 @@
+package goboye
+
+import (
+	"errors"
+	"github.com/mr-tim/goboye/internal/pkg/romfile"
+	"log"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// flush dirty save ram every few seconds, so a crash doesn't lose progress
+const saveFlushFrames = 5 * 60
+
+// savePathForRom gives the .sav file that sits next to a rom, eg:
+// roms/game.gb -> roms/game.sav
+// roms/all.zip#game.gb -> roms/game.sav
+func savePathForRom(romPath string) string {
+	return romSiblingPath(romPath, ".sav")
+}
+
+// romSiblingPath swaps a rom's extension for ext. Roms inside a zip take the
+// name of their entry, in the zip's directory.
+func romSiblingPath(romPath string, ext string) string {
+	file, entry := romfile.SplitPath(romPath)
+	if entry != "" {
+		file = filepath.Join(filepath.Dir(file), path.Base(entry))
+	}
+	return strings.TrimSuffix(file, filepath.Ext(file)) + ext
+}
+
+// writeFileAtomic writes to a temporary file in the same directory, then
+// renames it into place, so readers only ever see a complete file.
+func writeFileAtomic(path string, data []byte) error {
+	f, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+
+	if _, err = f.Write(data); err != nil {
+		f.Close()
+		return err
+	}
+	if err = f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+	if err = f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), path)
+}
+
+func (e *Emulator) loadSaveFile() {
+	if !e.memory.HasBattery() {
+		return
+	}
+	data, err := os.ReadFile(e.savePath)
+	if err != nil {
+		if !errors.Is(err, os.ErrNotExist) {
+			log.Printf("Unable to read save file %s: %s", e.savePath, err)
+		}
+		return
+	}
+	if err = e.memory.LoadSaveRam(data); err != nil {
+		log.Printf("Ignoring save file %s: %s", e.savePath, err)
+		return
+	}
+	e.memory.MarkSaveRamClean()
+	log.Printf("Loaded save file: %s", e.savePath)
+}
+
+// ExportSaveRam returns the cartridge's save ram, in .sav file layout.
+func (e *Emulator) ExportSaveRam() []byte {
+	return e.memory.SaveRam()
+}
+
+// ImportSaveRam replaces the cartridge's save ram with the contents of a .sav
+// file. It will be written to the rom's save file at the next flush.
+func (e *Emulator) ImportSaveRam(data []byte) error {
+	if err := e.memory.LoadSaveRam(data); err != nil {
+		return err
+	}
+	e.saveDirty = true
+	return nil
+}
+
+// FlushSaveRam writes battery backed ram to the rom's save file if it has
+// changed since the last flush.
+func (e *Emulator) FlushSaveRam() error {
+	if e.memory == nil || !e.memory.HasBattery() || e.savePath == "" {
+		return nil
+	}
+	if !e.memory.IsSaveRamDirty() && !e.saveDirty {
+		return nil
+	}
+	if err := writeFileAtomic(e.savePath, e.memory.SaveRam()); err != nil {
+		return err
+	}
+	e.memory.MarkSaveRamClean()
+	e.saveDirty = false
+	return nil
+}
+
+func (e *Emulator) periodicFlush() {
+	e.framesSinceFlush += 1
+	if e.framesSinceFlush < saveFlushFrames {
+		return
+	}
+	e.framesSinceFlush = 0
+	if err := e.FlushSaveRam(); err != nil {
+		log.Printf("Unable to write save file %s: %s", e.savePath, err)
+	}
+}
+
+// Close writes any unsaved save ram. It should be called when the emulator
+// exits.
+func (e *Emulator) Close() error {
+	if e.trace != nil {
+		if err := e.trace.Flush(); err != nil {
+			return err
+		}
+	}
+	return e.FlushSaveRam()
+}
@@ -0,0 +1,43 @@
+package goboye
+
+import (
+	"errors"
+	"github.com/mr-tim/goboye/internal/pkg/cheats"
+)
+
+var ErrNoRamSearch = errors.New("no ram search has been started")
+
+// StartRamSearch starts a new cheat finder search from the current contents
+// of ram, replacing any earlier search. It returns the number of addresses
+// being searched.
+func (e *Emulator) StartRamSearch(width cheats.Width) int {
+	e.ramSearch = cheats.NewSearch(e.memory.ReadAll(), width)
+	return e.ramSearch.Count()
+}
+
+// FilterRamSearch narrows the search down using the current contents of ram,
+// returning the number of addresses left.
+func (e *Emulator) FilterRamSearch(comparison cheats.Comparison, value uint16) (int, error) {
+	if e.ramSearch == nil {
+		return 0, ErrNoRamSearch
+	}
+	return e.ramSearch.Filter(e.memory.ReadAll(), comparison, value), nil
+}
+
+func (e *Emulator) ClearRamSearch() {
+	e.ramSearch = nil
+}
+
+// RamSearch gives the running search, or nil if there isn't one.
+func (e *Emulator) RamSearch() *cheats.Search {
+	return e.ramSearch
+}
+
+// RamSearchResults gives up to limit of the addresses left in the search,
+// with their current values.
+func (e *Emulator) RamSearchResults(limit int) []cheats.SearchResult {
+	if e.ramSearch == nil {
+		return nil
+	}
+	return e.ramSearch.Results(e.memory.ReadAll(), limit)
+}
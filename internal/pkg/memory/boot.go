@@ -1,6 +1,18 @@
 package memory
 
-var bootRom = []byte{
+import (
+	"fmt"
+	"github.com/mr-tim/goboye/internal/pkg/display/register"
+)
+
+const cgbBootRomSize = 0x0900
+
+// cartridge header, which the CGB boot rom leaves visible
+const cgbBootRomGapStart = 0x0100
+const cgbBootRomGapEnd = 0x01FF
+
+// dmgBootRom is used unless another boot rom is loaded.
+var dmgBootRom = []byte{
 	0x31, 0xfe, 0xff, 0xaf, 0x21, 0xff, 0x9f, 0x32, 0xcb, 0x7c, 0x20, 0xfb, 0x21, 0x26, 0xff, 0x0e,
 	0x11, 0x3e, 0x80, 0x32, 0xe2, 0x0c, 0x3e, 0xf3, 0xe2, 0x32, 0x3e, 0x77, 0x77, 0x3e, 0xfc, 0xe0,
 	0x47, 0x11, 0x04, 0x01, 0x21, 0x10, 0x80, 0x1a, 0xcd, 0x95, 0x00, 0xcd, 0x96, 0x00, 0x13, 0x7b,
@@ -20,7 +32,7 @@ var bootRom = []byte{
 }
 
 // noop boot rom
-//var bootRom = []byte{
+//var dmgBootRom = []byte{
 //	0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
 //	0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
 //	0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
@@ -38,3 +50,72 @@ var bootRom = []byte{
 //	0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
 //	0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x3e, 0x01, 0xe0, 0x50,
 //}
+
+// CheckBootRom checks that a boot rom image is a size that can be mapped.
+func CheckBootRom(data []byte) error {
+	if len(data) != BOOT_ROM_SIZE && len(data) != cgbBootRomSize {
+		return fmt.Errorf("boot rom is %d bytes, expected %d (DMG, MGB or SGB) or %d (CGB)",
+			len(data), BOOT_ROM_SIZE, cgbBootRomSize)
+	}
+	return nil
+}
+
+// LoadBootRom replaces the built in DMG boot rom.
+func (c *Controller) LoadBootRom(data []byte) error {
+	if err := CheckBootRom(data); err != nil {
+		return err
+	}
+	c.bootRom = data
+	c.romMapVersion++
+	return nil
+}
+
+/*
+	Post-boot state - what the boot rom leaves in the io registers when it
+	hands over to the cartridge at 0x0100. Sound registers aren't emulated,
+	but are set so games reading them back see the right values.
+*/
+
+var postBootIo = []struct {
+	addr  uint16
+	value byte
+}{
+	{0xFF00, 0xCF}, {0xFF01, 0x00}, {0xFF02, 0x7E}, {0xFF05, 0x00},
+	{0xFF06, 0x00}, {0xFF07, 0xF8}, {0xFF0F, 0xE1}, {0xFF10, 0x80},
+	{0xFF11, 0xBF}, {0xFF12, 0xF3}, {0xFF13, 0xFF}, {0xFF14, 0xBF},
+	{0xFF16, 0x3F}, {0xFF17, 0x00}, {0xFF18, 0xFF}, {0xFF19, 0xBF},
+	{0xFF1A, 0x7F}, {0xFF1B, 0xFF}, {0xFF1C, 0x9F}, {0xFF1D, 0xFF},
+	{0xFF1E, 0xBF}, {0xFF20, 0xFF}, {0xFF21, 0x00}, {0xFF22, 0x00},
+	{0xFF23, 0xBF}, {0xFF24, 0x77}, {0xFF25, 0xF3}, {0xFF40, 0x91},
+	{0xFF42, 0x00}, {0xFF43, 0x00}, {0xFF45, 0x00}, {0xFF47, 0xFC},
+	{0xFF4A, 0x00}, {0xFF4B, 0x00}, {0xFFFF, 0x00},
+}
+
+// SkipBootRom puts the hardware in the state the model's boot rom leaves it
+// in, and unmaps the boot rom. The cpu needs the same treatment - see
+// cpu.NewProcessorAfterBoot.
+func (c *Controller) SkipBootRom() {
+	for _, r := range postBootIo {
+		c.WriteAddr(r.addr, r.value)
+	}
+
+	// NR52 - sound on, with channel 1 still playing the boot sound (the SGB
+	// boot rom doesn't play it)
+	if c.model == SGB {
+		c.WriteAddr(0xFF26, 0xF0)
+	} else {
+		c.WriteAddr(0xFF26, 0xF1)
+	}
+	// DIV depends on how long the boot took - only the DMG/MGB value is fixed
+	if c.model == DMG || c.model == MGB {
+		c.Timer.setCounter(0xABCC)
+	} else {
+		c.Timer.setCounter(0x0000)
+	}
+	c.scheduleFrameSequencer()
+	c.StatFlags.SetMode(register.VerticalBlank)
+	c.LY.Write(0x00)
+	c.dmaStart = 0xFF
+
+	c.BootRomRegister.Write(bootRomDisabledValue)
+}
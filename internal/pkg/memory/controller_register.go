@@ -6,6 +6,7 @@ import (
 )
 
 type controllerRegister struct {
+	selectBits byte
 	selectRow1 bool
 	selectRow2 bool
 	buttonDown map[button.Button]bool
@@ -16,20 +17,30 @@ var row2 = []button.Button{button.A, button.B, button.Select, button.Start}
 
 func NewControllerRegister() controllerRegister {
 	return controllerRegister{
+		selectBits: 0x30,
 		buttonDown: make(map[button.Button]bool),
 	}
 }
 
+/*
+	P1 (0xFF00) - joypad
+
+	4 - select the direction row (0: selected)
+	5 - select the button row (0: selected)
+	0-3 - the selected rows' lines (0: pressed). Lines are shared between
+		rows, so with both selected a line is low if either button is held.
+*/
+
 func (r *controllerRegister) Read() byte {
-	// output is pinned high by default
-	selection := uint8(0x00)
+	// unused bits, and lines nothing is pulling low, read high
+	lines := uint8(0x0F)
 	if r.selectRow1 {
-		selection |= 0x01<<4 | r.row(row1)
+		lines &= r.row(row1)
 	}
 	if r.selectRow2 {
-		selection |= 0x01<<5 | r.row(row2)
+		lines &= r.row(row2)
 	}
-	return selection
+	return 0xC0 | r.selectBits | lines
 }
 
 func (r *controllerRegister) row(bs []button.Button) uint8 {
@@ -43,8 +54,15 @@ func (r *controllerRegister) row(bs []button.Button) uint8 {
 }
 
 func (r *controllerRegister) Write(value byte) {
-	r.selectRow1 = utils.IsBitSet(value, 5)
-	r.selectRow2 = utils.IsBitSet(value, 4)
+	r.selectBits = value & 0x30
+	r.selectRow1 = !utils.IsBitSet(value, 4)
+	r.selectRow2 = !utils.IsBitSet(value, 5)
+}
+
+// IsLineLow reports whether a button is held in a selected row, which is
+// what wakes the cpu from STOP.
+func (r *controllerRegister) IsLineLow() bool {
+	return r.Read()&0x0F != 0x0F
 }
 
 func (r *controllerRegister) SetButtonState(button button.Button, isDown bool) {
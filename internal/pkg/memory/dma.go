@@ -0,0 +1,38 @@
+package memory
+
+import "github.com/mr-tim/goboye/internal/pkg/scheduler"
+
+/*
+	DMA - 0xFF46
+
+	Writing XX copies XX00-XX9F into OAM (0xFE00-0xFE9F), a byte per M-cycle.
+	The copy is done in one go when the transfer ends.
+*/
+
+const (
+	oamStart  uint16 = 0xFE00
+	oamLength        = 0xA0
+	dmaCycles        = oamLength * 4
+)
+
+func (c *Controller) startDma(value byte) {
+	if value < 0x80 || value >= 0xE0 {
+		return
+	}
+	c.dmaStart = value
+	c.dmaActive = true
+	c.scheduler.Schedule(scheduler.DmaEnd, dmaCycles)
+}
+
+func (c *Controller) finishDma() {
+	source := uint16(c.dmaStart) << 8
+	for i := uint16(0); i < oamLength; i++ {
+		c.WriteAddr(oamStart+i, c.ReadAddr(source+i))
+	}
+	c.dmaActive = false
+}
+
+// IsDmaActive reports whether an OAM DMA transfer is under way.
+func (c *Controller) IsDmaActive() bool {
+	return c.dmaActive
+}
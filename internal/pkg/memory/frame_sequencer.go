@@ -0,0 +1,47 @@
+package memory
+
+import "github.com/mr-tim/goboye/internal/pkg/scheduler"
+
+/*
+	Frame sequencer
+
+	The part of the APU that clocks the sound channels - length counters on
+	steps 0, 2, 4 and 6, sweep on 2 and 6 and volume envelopes on 7. It steps
+	on the falling edge of DIV bit 4 (bit 5 in double speed), 512 times a
+	second, so resetting DIV can step it early.
+
+	The channels themselves aren't emulated yet - this just keeps the step.
+*/
+
+type FrameSequencer struct {
+	step  byte
+	ticks uint64
+}
+
+func (f *FrameSequencer) tick() {
+	f.step = (f.step + 1) & 0x07
+	f.ticks++
+}
+
+// Step is the step the sequencer is on, 0-7.
+func (f *FrameSequencer) Step() byte {
+	return f.step
+}
+
+// Ticks is how many times the sequencer has stepped since power on.
+func (f *FrameSequencer) Ticks() uint64 {
+	return f.ticks
+}
+
+// frameSequencerBit is the system counter bit the frame sequencer follows -
+// DIV bit 4, or 5 in double speed.
+func (c *Controller) frameSequencerBit() uint {
+	if c.SpeedSwitch.IsDoubleSpeed() {
+		return 13
+	}
+	return 12
+}
+
+func (c *Controller) scheduleFrameSequencer() {
+	c.scheduler.Schedule(scheduler.FrameSequencer, c.Timer.untilFallingEdge(c.frameSequencerBit()))
+}
@@ -0,0 +1,104 @@
+package memory
+
+import (
+	"github.com/mr-tim/goboye/internal/pkg/display/register"
+	"github.com/mr-tim/goboye/internal/pkg/scheduler"
+)
+
+/*
+	LCD status interrupt
+
+	The STAT interrupt is requested when any of its enabled sources becomes
+	true - mode 0, mode 1, mode 2 or LY=LYC - but they're ORed into a single
+	line, and only a rising edge counts. So while one source holds the line
+	high, the others can't request another interrupt ("STAT blocking").
+
+	Line 144 raises the mode 2 source as well as the mode 1 one, as if OAM
+	search was starting.
+*/
+
+// the first vblank line
+const vblankStartLine = 144
+
+// UpdateStatInterrupt compares LY with LYC, and requests the STAT interrupt
+// if the line has gone high. The display calls it whenever the mode or LY
+// changes, and writing LYC or STAT does too.
+func (c *Controller) UpdateStatInterrupt() {
+	if !c.LCDCFlags.IsLCDEnabled() {
+		c.statLine = false
+		return
+	}
+	ly := c.LY.Read()
+	c.StatFlags.SetCoincidence(ly == c.LYC.Read())
+
+	mode := c.StatFlags.GetMode()
+	line := (c.StatFlags.IsCoincidence() && c.StatFlags.IsInterruptEnabled(register.LycMatch)) ||
+		(mode == register.EnableCPUAccessToDisplayRAM && c.StatFlags.IsInterruptEnabled(register.Mode00)) ||
+		(mode == register.VerticalBlank && c.StatFlags.IsInterruptEnabled(register.Mode01)) ||
+		(mode == register.SearchingOAMRAM && c.StatFlags.IsInterruptEnabled(register.Mode10)) ||
+		(mode == register.VerticalBlank && ly == vblankStartLine && c.StatFlags.IsInterruptEnabled(register.Mode10))
+	if line && !c.statLine {
+		c.InterruptFlags.LcdStatusInterrupt()
+	}
+	c.statLine = line
+}
+
+// switching the lcd on or off restarts the display straight away
+type lcdcRegister Controller
+
+func (r *lcdcRegister) Read() byte {
+	return r.LCDCFlags.Read()
+}
+
+func (r *lcdcRegister) Write(value byte) {
+	wasEnabled := r.LCDCFlags.IsLCDEnabled()
+	r.LCDCFlags.Write(value)
+	if wasEnabled != r.LCDCFlags.IsLCDEnabled() {
+		r.scheduler.Schedule(scheduler.DisplayMode, 0)
+	}
+}
+
+type statRegister Controller
+
+func (r *statRegister) Read() byte {
+	return r.StatFlags.Read()
+}
+
+func (r *statRegister) Write(value byte) {
+	r.StatFlags.Write(value)
+	(*Controller)(r).UpdateStatInterrupt()
+}
+
+type lycRegister Controller
+
+func (r *lycRegister) Read() byte {
+	return r.LYC.Read()
+}
+
+func (r *lycRegister) Write(value byte) {
+	r.LYC.Write(value)
+	(*Controller)(r).UpdateStatInterrupt()
+}
+
+// doctorLy is what LY reads while SetDoctorLy is on
+const doctorLy = 0x90
+
+// SetDoctorLy makes LY always read 0x90, as it does in the Gameboy Doctor
+// reference logs, so traces can match them line for line. The display still
+// counts lines as usual.
+func (c *Controller) SetDoctorLy(enabled bool) {
+	c.doctorLy = enabled
+}
+
+type lyRegister Controller
+
+func (r *lyRegister) Read() byte {
+	if r.doctorLy {
+		return doctorLy
+	}
+	return r.LY.Read()
+}
+
+func (r *lyRegister) Write(value byte) {
+	r.LY.Write(value)
+}
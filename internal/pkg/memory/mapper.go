@@ -0,0 +1,136 @@
+package memory
+
+import (
+	"fmt"
+	"github.com/mr-tim/goboye/internal/pkg/cartridge"
+	"log"
+)
+
+const romBankSize = 0x4000
+const ramBankSize = 0x2000
+
+const cartRamStart uint16 = 0xA000
+const cartRamEnd uint16 = 0xBFFF
+
+// Mapper is the memory bank controller inside a cartridge. It sees every
+// access to the rom area (0x0000-0x7FFF) and the external ram area
+// (0xA000-0xBFFF).
+type Mapper interface {
+	ReadRom(addr uint16) byte
+	WriteRom(addr uint16, value byte)
+	ReadRam(addr uint16) byte
+	// WriteRam reports whether the write was stored - it isn't while ram is
+	// disabled, or if there's none.
+	WriteRam(addr uint16, value byte) bool
+	// RomBank is the bank currently mapped at 0x4000-0x7FFF.
+	RomBank() int
+}
+
+// batteryMapper is implemented by mappers with ram that can be battery
+// backed, so it can be saved and restored between runs.
+type batteryMapper interface {
+	saveData() []byte
+	loadSaveData(data []byte) error
+}
+
+// rtcMapper is implemented by mappers with a real-time clock.
+type rtcMapper interface {
+	setClock(clock RtcClock)
+}
+
+func newMapper(header cartridge.Header, rom []byte, clock RtcClock) Mapper {
+	ramSize := header.RamSize()
+	switch header.Type.Mapper() {
+	case cartridge.Mbc1:
+		return newMbc1(rom, ramSize)
+	case cartridge.Mbc2:
+		return newMbc2(rom)
+	case cartridge.Mbc3:
+		return newMbc3(rom, ramSize, header.Type.HasTimer(), clock)
+	case cartridge.Mbc5:
+		return newMbc5(rom, ramSize, header.Type.HasRumble())
+	case cartridge.UnsupportedMapper:
+		log.Printf("Unsupported cartridge type %s, running without a mapper", header.Type)
+	}
+	return newRomOnlyMapper(rom, ramSize)
+}
+
+func copyRam(ram []byte) []byte {
+	data := make([]byte, len(ram))
+	copy(data, ram)
+	return data
+}
+
+func loadRam(ram []byte, data []byte) error {
+	if len(data) != len(ram) {
+		return fmt.Errorf("save data is %d bytes, expected %d", len(data), len(ram))
+	}
+	copy(ram, data)
+	return nil
+}
+
+// padRom rounds the rom up to a whole number of banks (at least two), so
+// bank lookups never run off the end of a short or truncated image.
+func padRom(rom []byte) []byte {
+	size := 2 * romBankSize
+	for size < len(rom) {
+		size *= 2
+	}
+	padded := make([]byte, size)
+	copy(padded, rom)
+	return padded
+}
+
+// romOnlyMapper is a 32KiB cartridge with no mapper, and optionally up to
+// 8KiB of ram.
+type romOnlyMapper struct {
+	rom memoryMap
+	ram []byte
+}
+
+func newRomOnlyMapper(rom []byte, ramSize int) *romOnlyMapper {
+	if ramSize > ramBankSize {
+		ramSize = ramBankSize
+	}
+	m := romOnlyMapper{
+		rom: memoryMap{make([]byte, ROM_SIZE)},
+		ram: make([]byte, ramSize),
+	}
+	copy(m.rom.mem, rom)
+	return &m
+}
+
+func (m *romOnlyMapper) ReadRom(addr uint16) byte {
+	return m.rom.ReadAddr(addr)
+}
+
+func (m *romOnlyMapper) WriteRom(addr uint16, value byte) {}
+
+func (m *romOnlyMapper) ReadRam(addr uint16) byte {
+	offset := int(addr - cartRamStart)
+	if offset < len(m.ram) {
+		return m.ram[offset]
+	}
+	return 0xFF
+}
+
+func (m *romOnlyMapper) WriteRam(addr uint16, value byte) bool {
+	offset := int(addr - cartRamStart)
+	if offset < len(m.ram) {
+		m.ram[offset] = value
+		return true
+	}
+	return false
+}
+
+func (m *romOnlyMapper) RomBank() int {
+	return 1
+}
+
+func (m *romOnlyMapper) saveData() []byte {
+	return copyRam(m.ram)
+}
+
+func (m *romOnlyMapper) loadSaveData(data []byte) error {
+	return loadRam(m.ram, data)
+}
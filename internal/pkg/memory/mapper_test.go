@@ -0,0 +1,353 @@
+package memory
+
+import (
+	"github.com/mr-tim/goboye/internal/pkg/cartridge"
+	"github.com/mr-tim/goboye/internal/pkg/utils"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+// bankedRom builds a rom where the first byte of every bank holds the bank
+// number, with the given cartridge type and ram size header bytes.
+func bankedRom(banks int, cartType, ramSize byte) []byte {
+	rom := make([]byte, banks*romBankSize)
+	for b := 0; b < banks; b++ {
+		rom[b*romBankSize] = byte(b)
+	}
+	rom[0x0147] = cartType
+	rom[0x0149] = ramSize
+	return rom
+}
+
+func newMapperForRom(rom []byte, clock RtcClock) Mapper {
+	header, _ := cartridge.Parse(rom)
+	return newMapper(header, rom, clock)
+}
+
+func TestRomOnlyMapperIgnoresWrites(t *testing.T) {
+	c := NewControllerWithBytes(bankedRom(2, 0x00, 0x00))
+	c.BootRomRegister.Write(0x01)
+
+	c.WriteAddr(0x2000, 0x01)
+	assert.Equal(t, uint8(0x01), c.ReadAddr(0x4000))
+	c.WriteAddr(0xA000, 0x12)
+	assert.Equal(t, uint8(0xFF), c.ReadAddr(0xA000))
+}
+
+func TestMbc1RomBanking(t *testing.T) {
+	c := NewControllerWithBytes(bankedRom(128, 0x01, 0x00))
+	c.BootRomRegister.Write(0x01)
+
+	assert.Equal(t, uint8(0x01), c.ReadAddr(0x4000))
+
+	c.WriteAddr(0x2000, 0x05)
+	assert.Equal(t, uint8(0x05), c.ReadAddr(0x4000))
+
+	// bank 0 is remapped to bank 1
+	c.WriteAddr(0x2000, 0x00)
+	assert.Equal(t, uint8(0x01), c.ReadAddr(0x4000))
+
+	// upper bits come from the 2 bit register
+	c.WriteAddr(0x2000, 0x03)
+	c.WriteAddr(0x4000, 0x02)
+	assert.Equal(t, uint8(0x43), c.ReadAddr(0x4000))
+	assert.Equal(t, uint8(0x00), c.ReadAddr(0x0000))
+
+	// mode 1 also switches the lower area
+	c.WriteAddr(0x6000, 0x01)
+	assert.Equal(t, uint8(0x40), c.ReadAddr(0x0000))
+}
+
+func TestRomMapVersion(t *testing.T) {
+	c := NewControllerWithBytes(bankedRom(4, 0x01, 0x00))
+	v := c.RomMapVersion()
+
+	c.BootRomRegister.Write(0x01)
+	assert.NotEqual(t, v, c.RomMapVersion())
+	v = c.RomMapVersion()
+
+	c.WriteAddr(0xC000, 0x12)
+	assert.Equal(t, v, c.RomMapVersion())
+
+	c.WriteAddr(0x2000, 0x02)
+	assert.NotEqual(t, v, c.RomMapVersion())
+}
+
+func TestMbc1BankNumberWrapsToRomSize(t *testing.T) {
+	c := NewControllerWithBytes(bankedRom(4, 0x01, 0x00))
+	c.BootRomRegister.Write(0x01)
+
+	c.WriteAddr(0x2000, 0x06)
+	assert.Equal(t, uint8(0x02), c.ReadAddr(0x4000))
+}
+
+func TestMbc1Ram(t *testing.T) {
+	c := NewControllerWithBytes(bankedRom(4, 0x03, 0x03))
+
+	// disabled ram reads as open bus and ignores writes
+	c.WriteAddr(0xA000, 0x12)
+	assert.Equal(t, uint8(0xFF), c.ReadAddr(0xA000))
+
+	c.WriteAddr(0x0000, 0x0A)
+	c.WriteAddr(0xA000, 0x12)
+	assert.Equal(t, uint8(0x12), c.ReadAddr(0xA000))
+
+	// in mode 0 the ram bank is fixed at 0
+	c.WriteAddr(0x4000, 0x02)
+	assert.Equal(t, uint8(0x12), c.ReadAddr(0xA000))
+
+	c.WriteAddr(0x6000, 0x01)
+	assert.Equal(t, uint8(0x00), c.ReadAddr(0xA000))
+	c.WriteAddr(0xBFFF, 0x34)
+	assert.Equal(t, uint8(0x34), c.ReadAddr(0xBFFF))
+
+	c.WriteAddr(0x4000, 0x00)
+	assert.Equal(t, uint8(0x12), c.ReadAddr(0xA000))
+	assert.Equal(t, uint8(0x00), c.ReadAddr(0xBFFF))
+
+	c.WriteAddr(0x0000, 0x00)
+	assert.Equal(t, uint8(0xFF), c.ReadAddr(0xA000))
+}
+
+func TestMbc3RomAndRamBanking(t *testing.T) {
+	c := NewControllerWithBytes(bankedRom(128, 0x13, 0x03))
+
+	c.WriteAddr(0x2000, 0x7F)
+	assert.Equal(t, uint8(0x7F), c.ReadAddr(0x4000))
+	c.WriteAddr(0x2000, 0x00)
+	assert.Equal(t, uint8(0x01), c.ReadAddr(0x4000))
+
+	c.WriteAddr(0x0000, 0x0A)
+	c.WriteAddr(0x4000, 0x03)
+	c.WriteAddr(0xA000, 0x56)
+	c.WriteAddr(0x4000, 0x00)
+	assert.Equal(t, uint8(0x00), c.ReadAddr(0xA000))
+	c.WriteAddr(0x4000, 0x03)
+	assert.Equal(t, uint8(0x56), c.ReadAddr(0xA000))
+}
+
+func latchRtc(c *Controller) {
+	c.WriteAddr(0x6000, 0x00)
+	c.WriteAddr(0x6000, 0x01)
+}
+
+func readRtc(c *Controller, reg byte) byte {
+	c.WriteAddr(0x4000, reg)
+	return c.ReadAddr(0xA000)
+}
+
+func advanceSeconds(clock *CycleClock, seconds int) {
+	clock.cycles += uint64(seconds) * utils.CPU_CYCLES_PER_SECOND
+}
+
+func TestMbc3RtcLatch(t *testing.T) {
+	clock := NewCycleClock()
+	c := NewController()
+	c.SetRtcClock(clock)
+	c.mapper = newMapperForRom(bankedRom(4, 0x10, 0x03), c.rtcClock)
+	c.WriteAddr(0x0000, 0x0A)
+
+	advanceSeconds(clock, 3725)
+	// nothing is visible until the clock is latched
+	assert.Equal(t, uint8(0), readRtc(&c, rtcSeconds))
+
+	latchRtc(&c)
+	assert.Equal(t, uint8(5), readRtc(&c, rtcSeconds))
+	assert.Equal(t, uint8(2), readRtc(&c, rtcMinutes))
+	assert.Equal(t, uint8(1), readRtc(&c, rtcHours))
+
+	// latched values are held while the clock keeps running
+	advanceSeconds(clock, 10)
+	assert.Equal(t, uint8(5), readRtc(&c, rtcSeconds))
+	latchRtc(&c)
+	assert.Equal(t, uint8(15), readRtc(&c, rtcSeconds))
+}
+
+func TestMbc3RtcHaltAndCarry(t *testing.T) {
+	clock := NewCycleClock()
+	c := NewController()
+	c.SetRtcClock(clock)
+	c.mapper = newMapperForRom(bankedRom(4, 0x0F, 0x00), c.rtcClock)
+	c.WriteAddr(0x0000, 0x0A)
+
+	// day 511, 23:59:59
+	c.WriteAddr(0x4000, rtcDayHigh)
+	c.WriteAddr(0xA000, 0x41)
+	c.WriteAddr(0x4000, rtcDayLow)
+	c.WriteAddr(0xA000, 0xFF)
+	c.WriteAddr(0x4000, rtcHours)
+	c.WriteAddr(0xA000, 23)
+	c.WriteAddr(0x4000, rtcMinutes)
+	c.WriteAddr(0xA000, 59)
+	c.WriteAddr(0x4000, rtcSeconds)
+	c.WriteAddr(0xA000, 59)
+
+	// halted clocks don't advance
+	advanceSeconds(clock, 5)
+	latchRtc(&c)
+	assert.Equal(t, uint8(59), readRtc(&c, rtcSeconds))
+
+	c.WriteAddr(0x4000, rtcDayHigh)
+	c.WriteAddr(0xA000, 0x01)
+	advanceSeconds(clock, 1)
+	latchRtc(&c)
+	assert.Equal(t, uint8(0), readRtc(&c, rtcSeconds))
+	assert.Equal(t, uint8(0), readRtc(&c, rtcHours))
+	assert.Equal(t, uint8(0), readRtc(&c, rtcDayLow))
+	assert.Equal(t, uint8(0x80), readRtc(&c, rtcDayHigh))
+}
+
+func TestMbc5RomBanking(t *testing.T) {
+	c := NewControllerWithBytes(bankedRom(512, 0x19, 0x00))
+
+	c.WriteAddr(0x2000, 0x00)
+	assert.Equal(t, uint8(0x00), c.ReadAddr(0x4000))
+	assert.Equal(t, 0, c.RomBank())
+
+	c.WriteAddr(0x2000, 0x23)
+	c.WriteAddr(0x3000, 0x01)
+	assert.Equal(t, 0x123, c.RomBank())
+	assert.Equal(t, uint8(0x23), c.ReadAddr(0x4000))
+}
+
+func TestMbc5RamAndRumble(t *testing.T) {
+	c := NewControllerWithBytes(bankedRom(4, 0x1E, 0x03))
+
+	c.WriteAddr(0x0000, 0x0A)
+	c.WriteAddr(0x4000, 0x0B)
+	assert.True(t, c.IsRumbling())
+	c.WriteAddr(0xA000, 0x77)
+
+	// the rumble bit doesn't select a ram bank
+	c.WriteAddr(0x4000, 0x03)
+	assert.False(t, c.IsRumbling())
+	assert.Equal(t, uint8(0x77), c.ReadAddr(0xA000))
+}
+
+func TestMbc2(t *testing.T) {
+	c := NewControllerWithBytes(bankedRom(16, 0x06, 0x00))
+
+	// address bit 8 selects the rom bank register
+	c.WriteAddr(0x2100, 0x0F)
+	assert.Equal(t, uint8(0x0F), c.ReadAddr(0x4000))
+	c.WriteAddr(0x0100, 0x00)
+	assert.Equal(t, uint8(0x01), c.ReadAddr(0x4000))
+
+	assert.Equal(t, uint8(0xFF), c.ReadAddr(0xA000))
+	c.WriteAddr(0x0000, 0x0A)
+	c.WriteAddr(0xA000, 0x5C)
+	assert.Equal(t, uint8(0xFC), c.ReadAddr(0xA000))
+	// ram is echoed every 512 bytes
+	assert.Equal(t, uint8(0xFC), c.ReadAddr(0xA200))
+	assert.Equal(t, uint8(0xFC), c.ReadAddr(0xBE00))
+}
+
+func TestRomOnlyMapperWithRam(t *testing.T) {
+	c := NewControllerWithBytes(bankedRom(2, 0x08, 0x02))
+
+	c.WriteAddr(0xA000, 0x12)
+	assert.Equal(t, uint8(0x12), c.ReadAddr(0xA000))
+
+	// without any ram, there's nothing to write
+	c = NewControllerWithBytes(bankedRom(2, 0x00, 0x00))
+	c.WriteAddr(0xA000, 0x12)
+	assert.False(t, c.IsSaveRamDirty())
+}
+
+func TestSaveRamRoundTrip(t *testing.T) {
+	c := NewControllerWithBytes(bankedRom(4, 0x03, 0x02))
+	assert.True(t, c.HasBattery())
+	assert.False(t, c.IsSaveRamDirty())
+
+	// writes while ram is disabled are dropped, so there's nothing to save
+	c.WriteAddr(0xA123, 0x42)
+	assert.False(t, c.IsSaveRamDirty())
+
+	c.WriteAddr(0x0000, 0x0A)
+	c.WriteAddr(0xA123, 0x42)
+	assert.True(t, c.IsSaveRamDirty())
+
+	saved := c.SaveRam()
+	assert.Equal(t, 0x2000, len(saved))
+	assert.Equal(t, uint8(0x42), saved[0x0123])
+
+	other := NewControllerWithBytes(bankedRom(4, 0x03, 0x02))
+	assert.Nil(t, other.LoadSaveRam(saved))
+	other.WriteAddr(0x0000, 0x0A)
+	assert.Equal(t, uint8(0x42), other.ReadAddr(0xA123))
+
+	assert.NotNil(t, other.LoadSaveRam(saved[:0x100]))
+}
+
+func TestSaveRamWithoutBattery(t *testing.T) {
+	c := NewControllerWithBytes(bankedRom(4, 0x02, 0x02))
+	assert.False(t, c.HasBattery())
+}
+
+func TestMbc3SaveRamIncludesRtc(t *testing.T) {
+	clock := NewCycleClock()
+	c := NewController()
+	c.SetRtcClock(clock)
+	c.mapper = newMapperForRom(bankedRom(4, 0x10, 0x02), c.rtcClock)
+	c.WriteAddr(0x0000, 0x0A)
+	advanceSeconds(clock, 125)
+
+	saved := c.SaveRam()
+	assert.Equal(t, 0x2000+rtcFooterSize, len(saved))
+
+	other := NewController()
+	other.SetRtcClock(NewCycleClock())
+	other.mapper = newMapperForRom(bankedRom(4, 0x10, 0x02), other.rtcClock)
+	assert.Nil(t, other.LoadSaveRam(saved))
+	other.WriteAddr(0x0000, 0x0A)
+	latchRtc(&other)
+	assert.Equal(t, uint8(5), readRtc(&other, rtcSeconds))
+	assert.Equal(t, uint8(2), readRtc(&other, rtcMinutes))
+}
+
+func TestMbc3RtcSavedWithCycleClockLoadsWithHostClock(t *testing.T) {
+	clock := NewCycleClock()
+	c := NewController()
+	c.SetRtcClock(clock)
+	c.mapper = newMapperForRom(bankedRom(4, 0x10, 0x02), c.rtcClock)
+	c.WriteAddr(0x0000, 0x0A)
+	advanceSeconds(clock, 125)
+	saved := c.SaveRam()
+
+	other := NewController()
+	other.mapper = newMapperForRom(bankedRom(4, 0x10, 0x02), other.rtcClock)
+	assert.Nil(t, other.LoadSaveRam(saved))
+	other.WriteAddr(0x0000, 0x0A)
+	latchRtc(&other)
+	// at most a second has passed on the host since the save
+	assert.InDelta(t, 5, readRtc(&other, rtcSeconds), 1)
+	assert.Equal(t, uint8(2), readRtc(&other, rtcMinutes))
+	assert.Equal(t, uint8(0), readRtc(&other, rtcDayLow))
+	assert.Equal(t, uint8(0), readRtc(&other, rtcDayHigh))
+}
+
+type swapRomByte struct {
+	addr  uint16
+	value byte
+}
+
+func (s swapRomByte) PatchRomRead(addr uint16, value byte) byte {
+	if addr == s.addr {
+		return s.value
+	}
+	return value
+}
+
+func TestRomReadHook(t *testing.T) {
+	c := NewControllerWithBytes(bankedRom(4, 0x01, 0x00))
+	c.SetRomReadHook(swapRomByte{0x4000, 0xAB})
+
+	assert.Equal(t, uint8(0xAB), c.ReadAddr(0x4000))
+	assert.Equal(t, uint8(0x00), c.ReadAddr(0x4001))
+
+	// the boot rom isn't patched
+	assert.Equal(t, dmgBootRom[0x00], c.ReadAddr(0x0000))
+
+	c.SetRomReadHook(nil)
+	assert.Equal(t, uint8(0x01), c.ReadAddr(0x4000))
+}
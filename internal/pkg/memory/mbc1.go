@@ -0,0 +1,101 @@
+package memory
+
+/*
+	MBC1 - up to 2MiB rom and 32KiB ram
+
+	0x0000-0x1FFF - ram enable (0x0A in the low nibble enables)
+	0x2000-0x3FFF - low 5 bits of the rom bank number (0 is treated as 1)
+	0x4000-0x5FFF - 2 bit register: ram bank, or bits 5-6 of the rom bank
+	0x6000-0x7FFF - banking mode select
+		0: the 2 bit register only affects 0x4000-0x7FFF
+		1: the 2 bit register also switches 0x0000-0x3FFF and the ram bank
+*/
+
+type mbc1 struct {
+	rom        []byte
+	ram        []byte
+	romBanks   int
+	ramEnabled bool
+	bank1      byte
+	bank2      byte
+	mode       byte
+}
+
+func newMbc1(rom []byte, ramSize int) *mbc1 {
+	rom = padRom(rom)
+	return &mbc1{
+		rom:      rom,
+		ram:      make([]byte, ramSize),
+		romBanks: len(rom) / romBankSize,
+		bank1:    0x01,
+	}
+}
+
+func (m *mbc1) ReadRom(addr uint16) byte {
+	if addr < romBankSize {
+		return m.rom[m.lowRomBank()*romBankSize+int(addr)]
+	}
+	return m.rom[m.RomBank()*romBankSize+int(addr-romBankSize)]
+}
+
+func (m *mbc1) WriteRom(addr uint16, value byte) {
+	switch {
+	case addr < 0x2000:
+		m.ramEnabled = value&0x0F == 0x0A
+	case addr < 0x4000:
+		m.bank1 = value & 0x1F
+		if m.bank1 == 0 {
+			m.bank1 = 1
+		}
+	case addr < 0x6000:
+		m.bank2 = value & 0x03
+	default:
+		m.mode = value & 0x01
+	}
+}
+
+func (m *mbc1) ReadRam(addr uint16) byte {
+	if offset, ok := m.ramOffset(addr); ok {
+		return m.ram[offset]
+	}
+	return 0xFF
+}
+
+func (m *mbc1) WriteRam(addr uint16, value byte) bool {
+	offset, ok := m.ramOffset(addr)
+	if ok {
+		m.ram[offset] = value
+	}
+	return ok
+}
+
+func (m *mbc1) RomBank() int {
+	return int(m.bank2<<5|m.bank1) % m.romBanks
+}
+
+func (m *mbc1) lowRomBank() int {
+	if m.mode == 0 {
+		return 0
+	}
+	return int(m.bank2<<5) % m.romBanks
+}
+
+func (m *mbc1) ramOffset(addr uint16) (int, bool) {
+	if !m.ramEnabled || len(m.ram) == 0 {
+		return 0, false
+	}
+	bank := 0
+	if m.mode == 1 {
+		bank = int(m.bank2)
+	}
+	offset := (bank*ramBankSize + int(addr-cartRamStart)) % len(m.ram)
+	return offset, true
+}
+
+func (m *mbc1) saveData() []byte {
+	return copyRam(m.ram)
+}
+
+func (m *mbc1) loadSaveData(data []byte) error {
+	return loadRam(m.ram, data)
+}
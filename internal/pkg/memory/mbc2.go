@@ -0,0 +1,78 @@
+package memory
+
+/*
+	MBC2 - up to 256KiB rom and 512x4 bits of built in ram
+
+	0x0000-0x3FFF - register selected by bit 8 of the address
+		bit 8 clear: ram enable (0x0A in the low nibble enables)
+		bit 8 set: 4 bit rom bank number (0 is treated as 1)
+	0xA000-0xA1FF - built in ram, only the low nibble of each byte is stored.
+		It is echoed through the rest of 0xA000-0xBFFF.
+*/
+
+const mbc2RamSize = 0x0200
+
+type mbc2 struct {
+	rom        []byte
+	ram        [mbc2RamSize]byte
+	romBanks   int
+	ramEnabled bool
+	romBank    byte
+}
+
+func newMbc2(rom []byte) *mbc2 {
+	rom = padRom(rom)
+	return &mbc2{
+		rom:      rom,
+		romBanks: len(rom) / romBankSize,
+		romBank:  0x01,
+	}
+}
+
+func (m *mbc2) ReadRom(addr uint16) byte {
+	if addr < romBankSize {
+		return m.rom[addr]
+	}
+	return m.rom[m.RomBank()*romBankSize+int(addr-romBankSize)]
+}
+
+func (m *mbc2) WriteRom(addr uint16, value byte) {
+	if addr >= romBankSize {
+		return
+	}
+	if addr&0x0100 == 0 {
+		m.ramEnabled = value&0x0F == 0x0A
+	} else {
+		m.romBank = value & 0x0F
+		if m.romBank == 0 {
+			m.romBank = 1
+		}
+	}
+}
+
+func (m *mbc2) ReadRam(addr uint16) byte {
+	if !m.ramEnabled {
+		return 0xFF
+	}
+	// the upper nibble isn't connected, and reads as 1s
+	return 0xF0 | m.ram[addr%mbc2RamSize]
+}
+
+func (m *mbc2) WriteRam(addr uint16, value byte) bool {
+	if m.ramEnabled {
+		m.ram[addr%mbc2RamSize] = value & 0x0F
+	}
+	return m.ramEnabled
+}
+
+func (m *mbc2) RomBank() int {
+	return int(m.romBank) % m.romBanks
+}
+
+func (m *mbc2) saveData() []byte {
+	return copyRam(m.ram[:])
+}
+
+func (m *mbc2) loadSaveData(data []byte) error {
+	return loadRam(m.ram[:], data)
+}
@@ -0,0 +1,129 @@
+package memory
+
+/*
+	MBC3 - up to 2MiB rom, 32KiB ram and an optional real-time clock
+
+	0x0000-0x1FFF - ram and rtc enable (0x0A in the low nibble enables)
+	0x2000-0x3FFF - 7 bit rom bank number (0 is treated as 1)
+	0x4000-0x5FFF - ram bank (0x00-0x03) or rtc register (0x08-0x0C)
+	0x6000-0x7FFF - latch clock data (write 0x00 then 0x01)
+*/
+
+type mbc3 struct {
+	rom        []byte
+	ram        []byte
+	romBanks   int
+	rtc        *rtc
+	ramEnabled bool
+	romBank    byte
+	ramBank    byte
+}
+
+func newMbc3(rom []byte, ramSize int, hasRtc bool, clock RtcClock) *mbc3 {
+	rom = padRom(rom)
+	m := mbc3{
+		rom:      rom,
+		ram:      make([]byte, ramSize),
+		romBanks: len(rom) / romBankSize,
+		romBank:  0x01,
+	}
+	if hasRtc {
+		m.rtc = newRtc(clock)
+	}
+	return &m
+}
+
+func (m *mbc3) ReadRom(addr uint16) byte {
+	if addr < romBankSize {
+		return m.rom[addr]
+	}
+	return m.rom[m.RomBank()*romBankSize+int(addr-romBankSize)]
+}
+
+func (m *mbc3) WriteRom(addr uint16, value byte) {
+	switch {
+	case addr < 0x2000:
+		m.ramEnabled = value&0x0F == 0x0A
+	case addr < 0x4000:
+		m.romBank = value & 0x7F
+		if m.romBank == 0 {
+			m.romBank = 1
+		}
+	case addr < 0x6000:
+		m.ramBank = value & 0x0F
+	default:
+		if m.rtc != nil {
+			m.rtc.writeLatch(value)
+		}
+	}
+}
+
+func (m *mbc3) ReadRam(addr uint16) byte {
+	if !m.ramEnabled {
+		return 0xFF
+	}
+	if m.isRtcSelected() {
+		return m.rtc.read(m.ramBank)
+	}
+	if offset, ok := m.ramOffset(addr); ok {
+		return m.ram[offset]
+	}
+	return 0xFF
+}
+
+func (m *mbc3) WriteRam(addr uint16, value byte) bool {
+	if !m.ramEnabled {
+		return false
+	}
+	if m.isRtcSelected() {
+		// the clock is saved along with the ram
+		m.rtc.write(m.ramBank, value)
+		return true
+	}
+	offset, ok := m.ramOffset(addr)
+	if ok {
+		m.ram[offset] = value
+	}
+	return ok
+}
+
+func (m *mbc3) RomBank() int {
+	return int(m.romBank) % m.romBanks
+}
+
+func (m *mbc3) setClock(clock RtcClock) {
+	if m.rtc != nil {
+		m.rtc.setClock(clock)
+	}
+}
+
+func (m *mbc3) isRtcSelected() bool {
+	return m.rtc != nil && m.ramBank >= rtcSeconds && m.ramBank <= rtcDayHigh
+}
+
+func (m *mbc3) ramOffset(addr uint16) (int, bool) {
+	if len(m.ram) == 0 || m.ramBank > 0x03 {
+		return 0, false
+	}
+	return (int(m.ramBank)*ramBankSize + int(addr-cartRamStart)) % len(m.ram), true
+}
+
+// saveData is the ram followed, for carts with a clock, by the 48 byte rtc
+// footer that other emulators append to their save files.
+func (m *mbc3) saveData() []byte {
+	data := copyRam(m.ram)
+	if m.rtc != nil {
+		data = append(data, m.rtc.marshal()...)
+	}
+	return data
+}
+
+func (m *mbc3) loadSaveData(data []byte) error {
+	if m.rtc != nil && len(data) > len(m.ram) {
+		if err := m.rtc.unmarshal(data[len(m.ram):]); err != nil {
+			return err
+		}
+		data = data[:len(m.ram)]
+	}
+	return loadRam(m.ram, data)
+}
@@ -0,0 +1,95 @@
+package memory
+
+/*
+	MBC5 - up to 8MiB rom and 128KiB ram
+
+	0x0000-0x1FFF - ram enable (0x0A enables)
+	0x2000-0x2FFF - low 8 bits of the rom bank number (bank 0 is allowed)
+	0x3000-0x3FFF - bit 8 of the rom bank number
+	0x4000-0x5FFF - ram bank (0x00-0x0F) - on rumble carts bit 3 drives the motor
+*/
+
+type mbc5 struct {
+	rom        []byte
+	ram        []byte
+	romBanks   int
+	hasRumble  bool
+	ramEnabled bool
+	romBank    uint16
+	ramBank    byte
+	rumble     bool
+}
+
+func newMbc5(rom []byte, ramSize int, hasRumble bool) *mbc5 {
+	rom = padRom(rom)
+	return &mbc5{
+		rom:       rom,
+		ram:       make([]byte, ramSize),
+		romBanks:  len(rom) / romBankSize,
+		hasRumble: hasRumble,
+		romBank:   0x01,
+	}
+}
+
+func (m *mbc5) ReadRom(addr uint16) byte {
+	if addr < romBankSize {
+		return m.rom[addr]
+	}
+	return m.rom[m.RomBank()*romBankSize+int(addr-romBankSize)]
+}
+
+func (m *mbc5) WriteRom(addr uint16, value byte) {
+	switch {
+	case addr < 0x2000:
+		m.ramEnabled = value == 0x0A
+	case addr < 0x3000:
+		m.romBank = m.romBank&0x100 | uint16(value)
+	case addr < 0x4000:
+		m.romBank = m.romBank&0xFF | uint16(value&0x01)<<8
+	case addr < 0x6000:
+		if m.hasRumble {
+			m.rumble = value&0x08 != 0
+			m.ramBank = value & 0x07
+		} else {
+			m.ramBank = value & 0x0F
+		}
+	}
+}
+
+func (m *mbc5) ReadRam(addr uint16) byte {
+	if offset, ok := m.ramOffset(addr); ok {
+		return m.ram[offset]
+	}
+	return 0xFF
+}
+
+func (m *mbc5) WriteRam(addr uint16, value byte) bool {
+	offset, ok := m.ramOffset(addr)
+	if ok {
+		m.ram[offset] = value
+	}
+	return ok
+}
+
+func (m *mbc5) RomBank() int {
+	return int(m.romBank) % m.romBanks
+}
+
+func (m *mbc5) IsRumbling() bool {
+	return m.rumble
+}
+
+func (m *mbc5) ramOffset(addr uint16) (int, bool) {
+	if !m.ramEnabled || len(m.ram) == 0 {
+		return 0, false
+	}
+	return (int(m.ramBank)*ramBankSize + int(addr-cartRamStart)) % len(m.ram), true
+}
+
+func (m *mbc5) saveData() []byte {
+	return copyRam(m.ram)
+}
+
+func (m *mbc5) loadSaveData(data []byte) error {
+	return loadRam(m.ram, data)
+}
@@ -1,20 +1,28 @@
 package memory
 
 import (
+	"fmt"
+	"github.com/mr-tim/goboye/internal/pkg/cartridge"
 	"github.com/mr-tim/goboye/internal/pkg/display/register"
-	"io"
-	"os"
+	"github.com/mr-tim/goboye/internal/pkg/scheduler"
+	"log"
 )
 
 type Controller struct {
-	romImage         memoryMap
+	header           cartridge.Header
+	mapper           Mapper
+	saveRamDirty     bool
+	rtcClock         RtcClock
+	model            Model
+	bootRom          []byte
+	romReadHook      RomReadHook
+	romMapVersion    uint32
+	scheduler        *scheduler.Scheduler
 	ram              memoryMap
 	stack            memoryMap
 	ControllerData   controllerRegister
-	Divider          divRegister
-	TimerCounter     simpleByteRegister
-	TimerModulo      simpleByteRegister
-	TimerController  timerController
+	Timer            Timer
+	FrameSequencer   FrameSequencer
 	BootRomRegister  bootRomByteRegister
 	LCDCFlags        register.LCDCFlags
 	StatFlags        register.StatFlags
@@ -25,25 +33,45 @@ type Controller struct {
 	BGP              simpleByteRegister
 	OBP0             simpleByteRegister
 	OBP1             simpleByteRegister
+	WY               simpleByteRegister
+	WX               simpleByteRegister
 	InterruptFlags   InterruptFlagsRegister
 	InterruptEnabled InterruptEnabledRegister
+	SpeedSwitch      speedSwitchRegister
 	SerialOutput     string
-	serialRequested  bool
 	dmaStart         byte
+	dmaActive        bool
+	statLine         bool
+	doctorLy         bool
+}
+
+// RomReadHook can change bytes as they're read from the cartridge rom, like a
+// Game Genie.
+type RomReadHook interface {
+	PatchRomRead(addr uint16, value byte) byte
 }
 
 func NewController() Controller {
-	return Controller{
-		romImage:       memoryMap{make([]byte, ROM_SIZE)},
+	s := scheduler.New()
+	c := Controller{
+		mapper:         newRomOnlyMapper(nil, 0),
+		rtcClock:       NewHostClock(),
+		bootRom:        dmgBootRom,
+		scheduler:      s,
 		ram:            memoryMap{make([]byte, STACK_START-ROM_SIZE)},
 		stack:          memoryMap{make([]byte, STACK_END-STACK_START+1)},
 		ControllerData: NewControllerRegister(),
+		Timer:          newTimer(s),
 	}
+	c.scheduleFrameSequencer()
+	return c
 }
 
 func NewControllerWithBytes(bytes []byte) Controller {
 	c := NewController()
-	c.romImage.initWithBytes(bytes)
+	// short test images have no header, and run without a mapper
+	c.header, _ = cartridge.Parse(bytes)
+	c.mapper = newMapper(c.header, bytes, c.rtcClock)
 	return c
 }
 
@@ -51,34 +79,44 @@ func (c *Controller) getRegister(addr uint16) (ByteRegister, bool) {
 	switch addr {
 	case 0xFF00:
 		return &c.ControllerData, true
-	case 0xFF04:
-		return &c.Divider, true
-	case 0xFF05:
-		return &c.TimerCounter, true
-	case 0xFF06:
-		return &c.TimerModulo, true
-	case 0xFF07:
-		return &c.TimerController, true
+	case DividerAddress:
+		return (*divRegister)(c), true
+	case TimerCounterAddress:
+		return (*timaRegister)(&c.Timer), true
+	case TimerModuloAddress:
+		return (*tmaRegister)(&c.Timer), true
+	case TimerControlAddress:
+		return (*tacRegister)(&c.Timer), true
 	case 0xFF0F:
 		return &c.InterruptFlags, true
 	case 0xFF40:
-		return &c.LCDCFlags, true
+		return (*lcdcRegister)(c), true
 	case 0xFF41:
-		return &c.StatFlags, true
+		return (*statRegister)(c), true
 	case 0xFF42:
 		return &c.SCY, true
 	case 0xFF43:
 		return &c.SCX, true
 	case 0xFF44:
-		return &c.LY, true
+		return (*lyRegister)(c), true
 	case 0xFF45:
-		return &c.LYC, true
+		return (*lycRegister)(c), true
 	case 0xFF47:
 		return &c.BGP, true
 	case 0xFF48:
 		return &c.OBP0, true
 	case 0xFF49:
 		return &c.OBP1, true
+	case 0xFF4A:
+		return &c.WY, true
+	case 0xFF4B:
+		return &c.WX, true
+	case 0xFF4D:
+		// KEY1 only exists on the CGB
+		if c.model == CGB {
+			return &c.SpeedSwitch, true
+		}
+		return nil, false
 	case bootRomRegisterAddr:
 		return &c.BootRomRegister, true
 	case 0xFFFF:
@@ -88,28 +126,166 @@ func (c *Controller) getRegister(addr uint16) (ByteRegister, bool) {
 	}
 }
 
-func (c *Controller) LoadRomImage(filename string) error {
-	f, err := os.Open(filename)
-	if err != nil {
-		return err
+// Scheduler holds the master clock. Everything that happens at a set time -
+// the display, timer, serial and DMA - is an event on it.
+func (c *Controller) Scheduler() *scheduler.Scheduler {
+	return c.scheduler
+}
+
+// HandleEvent runs the events that belong to the memory mapped hardware.
+// Others (the display's) are ignored.
+func (c *Controller) HandleEvent(event scheduler.Event) {
+	switch event {
+	case scheduler.TimerOverflow:
+		c.Timer.overflow()
+	case scheduler.TimerReload:
+		if c.Timer.reload() {
+			c.InterruptFlags.TimerOverflowInterrupt()
+		}
+	case scheduler.SerialTransfer:
+		c.finishSerialTransfer()
+	case scheduler.DmaEnd:
+		c.finishDma()
+	case scheduler.FrameSequencer:
+		c.FrameSequencer.tick()
+		c.scheduleFrameSequencer()
+	}
+}
+
+// ResetDivider clears the system counter, as writing DIV or STOP does. TIMA
+// and the frame sequencer tick if the counter bit they follow was set.
+func (c *Controller) ResetDivider() {
+	glitch := c.Timer.isCounterBitSet(c.frameSequencerBit())
+	c.Timer.resetDivider()
+	if glitch {
+		c.FrameSequencer.tick()
+	}
+	c.scheduleFrameSequencer()
+}
+
+func (c *Controller) LoadRom(romBytes []byte) error {
+	if len(romBytes) < ROM_SIZE {
+		return fmt.Errorf("rom image is %d bytes, expected at least %d", len(romBytes), ROM_SIZE)
 	}
 
-	romBytes := make([]byte, ROM_SIZE)
-	_, err = io.ReadAtLeast(f, romBytes, ROM_SIZE)
+	header, err := cartridge.Parse(romBytes)
 	if err != nil {
 		return err
 	}
+	if !header.IsHeaderChecksumValid() {
+		log.Printf("Warning: bad header checksum %02X (expected %02X) - real hardware would not boot this rom",
+			header.HeaderChecksum, header.ComputedHeaderChecksum())
+	}
+	if !header.IsGlobalChecksumValid() {
+		log.Printf("Warning: bad global checksum %04X (expected %04X)",
+			header.GlobalChecksum, header.ComputedGlobalChecksum())
+	}
+	log.Printf("Cartridge: %q, %s", header.Title, header.Type)
 
-	c.romImage.initWithBytes(romBytes)
+	c.header = header
+	c.mapper = newMapper(header, romBytes, c.rtcClock)
+	c.romMapVersion++
 
 	return nil
 }
 
+func (c *Controller) CartridgeHeader() cartridge.Header {
+	return c.header
+}
+
+// HasBattery reports whether the cartridge keeps its ram (and clock) when
+// switched off, so it should be persisted between runs.
+func (c *Controller) HasBattery() bool {
+	_, ok := c.mapper.(batteryMapper)
+	return ok && c.header.Type.HasBattery()
+}
+
+// SaveRam returns a copy of the cartridge's external ram, in the layout used
+// for .sav files.
+func (c *Controller) SaveRam() []byte {
+	if m, ok := c.mapper.(batteryMapper); ok {
+		return m.saveData()
+	}
+	return []byte{}
+}
+
+func (c *Controller) LoadSaveRam(data []byte) error {
+	m, ok := c.mapper.(batteryMapper)
+	if !ok {
+		return fmt.Errorf("cartridge type %s has no save ram", c.header.Type)
+	}
+	return m.loadSaveData(data)
+}
+
+// IsSaveRamDirty reports whether external ram has been written since the last
+// call to MarkSaveRamClean.
+func (c *Controller) IsSaveRamDirty() bool {
+	return c.saveRamDirty
+}
+
+func (c *Controller) MarkSaveRamClean() {
+	c.saveRamDirty = false
+}
+
+// RomBank is the rom bank currently mapped at 0x4000-0x7FFF.
+func (c *Controller) RomBank() int {
+	return c.mapper.RomBank()
+}
+
+// IsRumbling reports whether a rumble cartridge has its motor switched on.
+func (c *Controller) IsRumbling() bool {
+	if m, ok := c.mapper.(*mbc5); ok {
+		return m.IsRumbling()
+	}
+	return false
+}
+
+// SetModel picks the hardware to emulate. It should be set before the rom is
+// loaded.
+func (c *Controller) SetModel(model Model) {
+	c.model = model
+}
+
+func (c *Controller) Model() Model {
+	return c.model
+}
+
+// SetRtcClock picks the time source for cartridges with a real-time clock.
+func (c *Controller) SetRtcClock(clock RtcClock) {
+	c.rtcClock = clock
+	if m, ok := c.mapper.(rtcMapper); ok {
+		m.setClock(clock)
+	}
+}
+
+// SetRomReadHook installs a hook that sees every cartridge rom read, or
+// removes it if hook is nil.
+func (c *Controller) SetRomReadHook(hook RomReadHook) {
+	c.romReadHook = hook
+	c.romMapVersion++
+}
+
+// RomMapVersion changes whenever what's read from 0x0000-0x7FFF might have
+// changed - a write to the cartridge's bank registers, a new rom read hook or
+// the boot rom being switched off - so anything decoded from rom is only good
+// while it stays the same.
+func (c *Controller) RomMapVersion() uint32 {
+	if c.BootRomRegister.isDisabled {
+		return c.romMapVersion<<1 | 1
+	}
+	return c.romMapVersion << 1
+}
+
 func (c *Controller) ReadAddr(addr uint16) byte {
 	if c.isBootRoomAddr(addr) {
-		return bootRom[addr]
+		return c.bootRom[addr]
 	} else if c.isRomAddr(addr) {
-		return c.romImage.ReadAddr(addr)
+		if c.romReadHook != nil {
+			return c.romReadHook.PatchRomRead(addr, c.mapper.ReadRom(addr))
+		}
+		return c.mapper.ReadRom(addr)
+	} else if c.isCartRamAddr(addr) {
+		return c.mapper.ReadRam(addr)
 	} else if c.isRamAddr(addr) {
 		// working ram (ish)
 		// todo: protect against access to forbidden areas?
@@ -126,33 +302,25 @@ func (c *Controller) ReadAddr(addr uint16) byte {
 }
 
 func (c *Controller) WriteAddr(addr uint16, value byte) {
-	if c.isBootRoomAddr(addr) {
-		//panic("Ignoring request to write to boot rom")
-	} else if c.isRomAddr(addr) {
-		// todo: rom bank switching?
+	if c.isRomAddr(addr) {
+		// the boot rom only overlays reads - writes always reach the cartridge
+		c.mapper.WriteRom(addr, value)
+		c.romMapVersion++
+	} else if c.isCartRamAddr(addr) {
+		if c.mapper.WriteRam(addr, value) {
+			c.saveRamDirty = true
+		}
 	} else if c.isRamAddr(addr) {
 		// working ram
 		// todo: protect against access to forbidden areas?
 		c.ram.WriteAddr(addr-ROM_SIZE, value)
 	} else if reg, hasKey := c.getRegister(addr); hasKey {
 		reg.Write(value)
-	} else if addr == 0xFF01 {
-		c.stack.WriteAddr(addr-STACK_START, value)
-		if c.serialRequested {
-			c.SerialOutput += string(value)
-			c.WriteAddr(0xFF02, 0x00)
-		}
-	} else if addr == 0xFF02 {
+	} else if addr == serialControlAddress {
 		c.stack.WriteAddr(addr-STACK_START, value)
-		c.serialRequested = value == 0x81
+		c.startSerialTransfer(value)
 	} else if addr == 0xFF46 {
-		// do a DMA transfer
-		if value >= 0x80 && value < 0xE0 {
-			c.dmaStart = value
-			for i := 0; i < 0x0100; i += 1 {
-				c.WriteAddr(0xFE00+uint16(i), c.ReadAddr(0x100*uint16(c.dmaStart)+uint16(i)))
-			}
-		}
+		c.startDma(value)
 	} else if c.isStackAddr(addr) {
 		c.stack.WriteAddr(addr-STACK_START, value)
 	} else {
@@ -168,12 +336,19 @@ func (c *Controller) isRamAddr(addr uint16) bool {
 	return addr >= ROM_SIZE && addr < STACK_START
 }
 
+func (c *Controller) isCartRamAddr(addr uint16) bool {
+	return addr >= cartRamStart && addr <= cartRamEnd
+}
+
 func (c *Controller) isRomAddr(addr uint16) bool {
 	return addr < ROM_SIZE
 }
 
 func (c *Controller) isBootRoomAddr(addr uint16) bool {
-	return addr < BOOT_ROM_SIZE && !c.BootRomRegister.isDisabled
+	if c.BootRomRegister.isDisabled || int(addr) >= len(c.bootRom) {
+		return false
+	}
+	return addr < cgbBootRomGapStart || addr > cgbBootRomGapEnd
 }
 
 func (c *Controller) ReadAddrU16(addr uint16) uint16 {
@@ -1,6 +1,8 @@
 package memory
 
 import (
+	"github.com/mr-tim/goboye/internal/pkg/goboye/button"
+	"github.com/mr-tim/goboye/internal/pkg/scheduler"
 	"github.com/stretchr/testify/assert"
 	"io/ioutil"
 	"os"
@@ -31,3 +33,180 @@ func TestLoadRom(t *testing.T) {
 	assert.Equal(t, uint8(0x23), m.mem[0x0023])
 	assert.Equal(t, uint8(0x69), m.mem[0x4769])
 }
+
+func TestCgbBootRomLeavesHeaderVisible(t *testing.T) {
+	c := NewControllerWithBytes(bankedRom(2, 0x00, 0x00))
+	c.WriteAddr(0x0000, 0x00)
+	bootRom := make([]byte, cgbBootRomSize)
+	for i := range bootRom {
+		bootRom[i] = 0xEE
+	}
+	assert.Nil(t, c.LoadBootRom(bootRom))
+
+	assert.Equal(t, uint8(0xEE), c.ReadAddr(0x00FF))
+	assert.Equal(t, uint8(0x00), c.ReadAddr(0x0100))
+	assert.Equal(t, uint8(0xEE), c.ReadAddr(0x0200))
+	assert.Equal(t, uint8(0xEE), c.ReadAddr(0x08FF))
+	assert.Equal(t, uint8(0x00), c.ReadAddr(0x0900))
+
+	c.BootRomRegister.Write(0x01)
+	assert.Equal(t, uint8(0x00), c.ReadAddr(0x00FF))
+
+	assert.NotNil(t, c.LoadBootRom(make([]byte, 0x200)))
+}
+
+func TestSkipBootRom(t *testing.T) {
+	c := NewControllerWithBytes(bankedRom(2, 0x00, 0x00))
+	c.SkipBootRom()
+
+	assert.Equal(t, uint8(0x01), c.BootRomRegister.Read())
+	assert.Equal(t, uint8(0x00), c.ReadAddr(0x0000))
+	assert.Equal(t, uint8(0x91), c.ReadAddr(0xFF40))
+	assert.Equal(t, uint8(0xFC), c.ReadAddr(0xFF47))
+	assert.Equal(t, uint8(0xE1), c.ReadAddr(0xFF0F))
+	assert.Equal(t, uint8(0xAB), c.ReadAddr(0xFF04))
+	assert.Equal(t, uint8(0xF1), c.ReadAddr(0xFF26))
+
+	sgb := NewControllerWithBytes(bankedRom(2, 0x00, 0x00))
+	sgb.SetModel(SGB)
+	sgb.SkipBootRom()
+	assert.Equal(t, uint8(0xF0), sgb.ReadAddr(0xFF26))
+}
+
+func TestParseModel(t *testing.T) {
+	m, err := ParseModel("cgb")
+	assert.Nil(t, err)
+	assert.Equal(t, CGB, m)
+	assert.Equal(t, 0x900, m.BootRomSize())
+
+	_, err = ParseModel("gba")
+	assert.NotNil(t, err)
+}
+
+func TestJoypadRead(t *testing.T) {
+	c := NewController()
+	c.ControllerData.SetButtonState(button.Start, true)
+	c.ControllerData.SetButtonState(button.Down, true)
+
+	// bit 5 low selects the buttons, and a held button reads 0
+	c.WriteAddr(0xFF00, 0x10)
+	assert.Equal(t, uint8(0xD7), c.ReadAddr(0xFF00))
+	c.ControllerData.SetButtonState(button.Start, false)
+	assert.Equal(t, uint8(0xDF), c.ReadAddr(0xFF00))
+
+	// bit 4 low selects the directions. The line bits can't be written.
+	c.WriteAddr(0xFF00, 0x2F)
+	assert.Equal(t, uint8(0xE7), c.ReadAddr(0xFF00))
+
+	// with neither row selected, every line reads high
+	c.WriteAddr(0xFF00, 0x30)
+	assert.Equal(t, uint8(0xFF), c.ReadAddr(0xFF00))
+}
+
+func TestJoypadRows(t *testing.T) {
+	c := NewController()
+	c.ControllerData.SetButtonState(button.A, true)
+	c.ControllerData.SetButtonState(button.Left, true)
+
+	// nothing selected
+	assert.Equal(t, uint8(0xFF), c.ReadAddr(0xFF00))
+	assert.False(t, c.ControllerData.IsLineLow())
+
+	c.WriteAddr(0xFF00, 0x20)
+	assert.Equal(t, uint8(0xED), c.ReadAddr(0xFF00))
+	c.WriteAddr(0xFF00, 0x10)
+	assert.Equal(t, uint8(0xDE), c.ReadAddr(0xFF00))
+	c.WriteAddr(0xFF00, 0x00)
+	assert.Equal(t, uint8(0xCC), c.ReadAddr(0xFF00))
+	assert.True(t, c.ControllerData.IsLineLow())
+}
+
+func TestSpeedSwitchIsCgbOnly(t *testing.T) {
+	c := NewController()
+	c.WriteAddr(0xFF4D, 0x01)
+	assert.False(t, c.SpeedSwitch.IsPrepared())
+
+	c.SetModel(CGB)
+	assert.Equal(t, uint8(0x7E), c.ReadAddr(0xFF4D))
+	c.WriteAddr(0xFF4D, 0x01)
+	assert.Equal(t, uint8(0x7F), c.ReadAddr(0xFF4D))
+	c.SpeedSwitch.Switch()
+	assert.Equal(t, uint8(0xFE), c.ReadAddr(0xFF4D))
+}
+
+func TestSpeedSwitchReschedules(t *testing.T) {
+	c := NewController()
+	c.SetModel(CGB)
+	advance(&c, 1000)
+	// the display is 100 cycles from its next mode
+	c.Scheduler().Schedule(scheduler.DisplayMode, 100)
+
+	c.SwitchSpeed()
+	until, _ := c.Scheduler().Until(scheduler.DisplayMode)
+	assert.Equal(t, uint64(200), until)
+	// the frame sequencer now follows bit 13
+	until, _ = c.Scheduler().Until(scheduler.FrameSequencer)
+	assert.Equal(t, uint64(16384-1000), until)
+
+	advance(&c, 100)
+	c.SwitchSpeed()
+	until, _ = c.Scheduler().Until(scheduler.DisplayMode)
+	assert.Equal(t, uint64(50), until)
+	until, _ = c.Scheduler().Until(scheduler.FrameSequencer)
+	assert.Equal(t, uint64(8192-1100), until)
+}
+
+func TestSerialTransferIsScheduled(t *testing.T) {
+	c := NewController()
+	c.WriteAddr(0xFF01, 'A')
+	c.WriteAddr(0xFF02, 0x81)
+	assert.Equal(t, "A", c.SerialOutput)
+
+	advance(&c, 8*serialBitCycles-4)
+	assert.Equal(t, uint8(0x81), c.ReadAddr(0xFF02))
+	assert.False(t, c.InterruptFlags.SerialLink())
+
+	advance(&c, 4)
+	assert.Equal(t, uint8(0x01), c.ReadAddr(0xFF02))
+	assert.Equal(t, uint8(0xFF), c.ReadAddr(0xFF01))
+	assert.True(t, c.InterruptFlags.SerialLink())
+}
+
+func TestDmaEndsAfterTransfer(t *testing.T) {
+	c := NewController()
+	for i := uint16(0); i < oamLength; i++ {
+		c.WriteAddr(0xC000+i, byte(i))
+	}
+	c.WriteAddr(0xFF46, 0xC0)
+	assert.True(t, c.IsDmaActive())
+
+	advance(&c, dmaCycles)
+	assert.False(t, c.IsDmaActive())
+	assert.Equal(t, uint8(0x00), c.ReadAddr(0xFE00))
+	assert.Equal(t, uint8(0x9F), c.ReadAddr(0xFE9F))
+}
+
+func TestFrameSequencerFollowsDivider(t *testing.T) {
+	c := NewController()
+	advance(&c, 8192*3)
+	assert.Equal(t, uint8(3), c.FrameSequencer.Step())
+
+	// resetting DIV with bit 4 set steps it early
+	advance(&c, 4096)
+	c.WriteAddr(DividerAddress, 0x00)
+	assert.Equal(t, uint8(4), c.FrameSequencer.Step())
+	advance(&c, 8191)
+	assert.Equal(t, uint8(4), c.FrameSequencer.Step())
+	advance(&c, 1)
+	assert.Equal(t, uint8(5), c.FrameSequencer.Step())
+}
+
+func TestDoctorLy(t *testing.T) {
+	c := NewController()
+	c.LY.Write(0x12)
+	assert.Equal(t, uint8(0x12), c.ReadAddr(0xFF44))
+
+	c.SetDoctorLy(true)
+	assert.Equal(t, uint8(0x90), c.ReadAddr(0xFF44))
+	assert.Equal(t, uint8(0x12), c.LY.Read())
+}
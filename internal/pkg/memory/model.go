@@ -0,0 +1,45 @@
+package memory
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Model is the Game Boy hardware being emulated. It picks the boot rom
+// layout and the state the boot rom leaves the hardware in.
+type Model int
+
+const (
+	DMG Model = iota
+	MGB
+	SGB
+	CGB
+)
+
+var modelNames = []string{"DMG", "MGB", "SGB", "CGB"}
+
+func (m Model) String() string {
+	if int(m) < len(modelNames) {
+		return modelNames[m]
+	}
+	return fmt.Sprintf("Model(%d)", int(m))
+}
+
+func ParseModel(name string) (Model, error) {
+	for i, n := range modelNames {
+		if strings.EqualFold(n, name) {
+			return Model(i), nil
+		}
+	}
+	return DMG, fmt.Errorf("unknown model %q, expected one of %s", name, strings.Join(modelNames, ", "))
+}
+
+// BootRomSize is the size of the model's boot rom. The CGB boot rom is
+// mapped at 0x0000-0x00FF and 0x0200-0x08FF, leaving the cartridge header
+// visible in between.
+func (m Model) BootRomSize() int {
+	if m == CGB {
+		return cgbBootRomSize
+	}
+	return BOOT_ROM_SIZE
+}
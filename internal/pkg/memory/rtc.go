@@ -0,0 +1,275 @@
+package memory
+
+import (
+	"encoding/binary"
+	"fmt"
+	"github.com/mr-tim/goboye/internal/pkg/utils"
+	"time"
+)
+
+// RtcClock is the time source for a cartridge real-time clock.
+type RtcClock interface {
+	Now() time.Time
+}
+
+type hostClock struct{}
+
+func (c hostClock) Now() time.Time {
+	return time.Now()
+}
+
+// NewHostClock returns a clock that follows the host's wall clock.
+func NewHostClock() RtcClock {
+	return hostClock{}
+}
+
+// CycleClock derives time from emulated cpu cycles, so the real-time clock
+// is deterministic from one run to the next.
+type CycleClock struct {
+	cycles uint64
+}
+
+func NewCycleClock() *CycleClock {
+	return &CycleClock{}
+}
+
+func (c *CycleClock) Advance(cycles uint8) {
+	c.cycles += uint64(cycles)
+}
+
+func (c *CycleClock) Now() time.Time {
+	seconds := c.cycles / utils.CPU_CYCLES_PER_SECOND
+	remainder := c.cycles % utils.CPU_CYCLES_PER_SECOND
+	nanos := remainder * uint64(time.Second) / utils.CPU_CYCLES_PER_SECOND
+	return time.Unix(int64(seconds), int64(nanos))
+}
+
+const (
+	rtcSeconds byte = 0x08
+	rtcMinutes byte = 0x09
+	rtcHours   byte = 0x0A
+	rtcDayLow  byte = 0x0B
+	rtcDayHigh byte = 0x0C
+)
+
+const rtcHaltBit = 6
+const rtcCarryBit = 7
+
+type rtcRegisters struct {
+	seconds byte
+	minutes byte
+	hours   byte
+	dayLow  byte
+	dayHigh byte
+}
+
+func (r *rtcRegisters) read(reg byte) byte {
+	switch reg {
+	case rtcSeconds:
+		return r.seconds
+	case rtcMinutes:
+		return r.minutes
+	case rtcHours:
+		return r.hours
+	case rtcDayLow:
+		return r.dayLow
+	case rtcDayHigh:
+		return r.dayHigh
+	}
+	return 0xFF
+}
+
+func (r *rtcRegisters) days() int {
+	return int(r.dayHigh&0x01)<<8 | int(r.dayLow)
+}
+
+func (r *rtcRegisters) setDays(days int) {
+	r.dayLow = byte(days)
+	r.dayHigh = r.dayHigh&0xFE | byte(days>>8)&0x01
+}
+
+/*
+	MBC3 real-time clock
+
+	The live counters tick in the background. Games read a latched copy,
+	taken by writing 0x00 then 0x01 to 0x6000-0x7FFF.
+
+	Day high register:
+		0: bit 8 of the day counter
+		6: halt (0: running, 1: stopped)
+		7: day counter carry (sticky, until written as 0)
+*/
+
+type rtc struct {
+	clock      RtcClock
+	live       rtcRegisters
+	latched    rtcRegisters
+	lastSync   time.Time
+	subSecond  time.Duration
+	latchArmed bool
+}
+
+func newRtc(clock RtcClock) *rtc {
+	return &rtc{
+		clock:    clock,
+		lastSync: clock.Now(),
+	}
+}
+
+func (r *rtc) setClock(clock RtcClock) {
+	r.sync()
+	r.clock = clock
+	r.lastSync = clock.Now()
+}
+
+func (r *rtc) isHalted() bool {
+	return utils.IsBitSet(r.live.dayHigh, rtcHaltBit)
+}
+
+func (r *rtc) sync() {
+	now := r.clock.Now()
+	elapsed := now.Sub(r.lastSync)
+	r.lastSync = now
+	if r.isHalted() || elapsed <= 0 {
+		return
+	}
+	r.subSecond += elapsed
+	seconds := r.subSecond / time.Second
+	r.subSecond -= seconds * time.Second
+	r.advance(int64(seconds))
+}
+
+func (r *rtc) advance(seconds int64) {
+	l := &r.live
+	if l.seconds < 60 && l.minutes < 60 && l.hours < 24 {
+		total := int64(l.days())*86400 + int64(l.hours)*3600 + int64(l.minutes)*60 + int64(l.seconds) + seconds
+		days := total / 86400
+		if days > 0x1FF {
+			l.dayHigh = utils.SetBit(l.dayHigh, rtcCarryBit)
+			days %= 0x200
+		}
+		l.setDays(int(days))
+		l.hours = byte(total % 86400 / 3600)
+		l.minutes = byte(total % 3600 / 60)
+		l.seconds = byte(total % 60)
+		return
+	}
+
+	// out of range values (which games can write) wrap at the register width
+	// without carrying, so count those a second at a time until they settle
+	for ; seconds > 0; seconds-- {
+		r.tickSecond()
+		if l.seconds < 60 && l.minutes < 60 && l.hours < 24 {
+			r.advance(seconds - 1)
+			return
+		}
+	}
+}
+
+func (r *rtc) tickSecond() {
+	l := &r.live
+	l.seconds = (l.seconds + 1) & 0x3F
+	if l.seconds != 60 {
+		return
+	}
+	l.seconds = 0
+	l.minutes = (l.minutes + 1) & 0x3F
+	if l.minutes != 60 {
+		return
+	}
+	l.minutes = 0
+	l.hours = (l.hours + 1) & 0x1F
+	if l.hours != 24 {
+		return
+	}
+	l.hours = 0
+	days := l.days() + 1
+	if days > 0x1FF {
+		l.dayHigh = utils.SetBit(l.dayHigh, rtcCarryBit)
+		days = 0
+	}
+	l.setDays(days)
+}
+
+func (r *rtc) writeLatch(value byte) {
+	if r.latchArmed && value == 0x01 {
+		r.sync()
+		r.latched = r.live
+	}
+	r.latchArmed = value == 0x00
+}
+
+func (r *rtc) read(reg byte) byte {
+	return r.latched.read(reg)
+}
+
+func (r *rtc) write(reg byte, value byte) {
+	r.sync()
+	switch reg {
+	case rtcSeconds:
+		r.live.seconds = value & 0x3F
+		r.subSecond = 0
+	case rtcMinutes:
+		r.live.minutes = value & 0x3F
+	case rtcHours:
+		r.live.hours = value & 0x1F
+	case rtcDayLow:
+		r.live.dayLow = value
+	case rtcDayHigh:
+		r.live.dayHigh = value & 0xC1
+	}
+}
+
+/*
+	rtc save footer - all values little endian
+
+	0x00-0x13 - live seconds, minutes, hours, day low, day high (4 bytes each)
+	0x14-0x27 - latched seconds, minutes, hours, day low, day high
+	0x28-0x2F - unix timestamp of the save (some emulators only write 4 bytes)
+
+	The timestamp is always the host's wall clock, whatever clock the rtc runs
+	from - a cycle clock starts at 1970, which would look like decades had
+	passed when the save is next loaded with the host clock.
+*/
+
+const rtcFooterSize = 48
+
+func (r *rtc) marshal() []byte {
+	r.sync()
+	data := make([]byte, rtcFooterSize)
+	for i, regs := range []rtcRegisters{r.live, r.latched} {
+		for j, v := range []byte{regs.seconds, regs.minutes, regs.hours, regs.dayLow, regs.dayHigh} {
+			binary.LittleEndian.PutUint32(data[i*20+j*4:], uint32(v))
+		}
+	}
+	binary.LittleEndian.PutUint64(data[40:], uint64(time.Now().Unix()))
+	return data
+}
+
+func (r *rtc) unmarshal(data []byte) error {
+	if len(data) != rtcFooterSize && len(data) != rtcFooterSize-4 {
+		return fmt.Errorf("rtc save data is %d bytes, expected %d", len(data), rtcFooterSize)
+	}
+	regs := make([]rtcRegisters, 2)
+	for i := range regs {
+		v := func(j int) byte {
+			return byte(binary.LittleEndian.Uint32(data[i*20+j*4:]))
+		}
+		regs[i] = rtcRegisters{v(0), v(1), v(2), v(3), v(4)}
+	}
+	r.live, r.latched = regs[0], regs[1]
+	r.subSecond = 0
+	r.lastSync = r.clock.Now()
+
+	// a host clock keeps running while the emulator is closed
+	if _, ok := r.clock.(hostClock); ok {
+		saved := int64(binary.LittleEndian.Uint32(data[40:]))
+		if len(data) == rtcFooterSize {
+			saved = int64(binary.LittleEndian.Uint64(data[40:]))
+		}
+		if elapsed := r.lastSync.Unix() - saved; elapsed > 0 && !r.isHalted() {
+			r.advance(elapsed)
+		}
+	}
+	return nil
+}
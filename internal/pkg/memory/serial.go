@@ -0,0 +1,51 @@
+package memory
+
+import (
+	"github.com/mr-tim/goboye/internal/pkg/scheduler"
+	"github.com/mr-tim/goboye/internal/pkg/utils"
+)
+
+/*
+	Serial - SB (0xFF01) and SC (0xFF02)
+
+	SC 0 - clock: 0 - external, 1 - internal
+	SC 1 - (CGB) internal clock speed: 0 - 8192Hz, 1 - 262144Hz
+	SC 7 - transfer requested/in progress
+
+	Writing SC with bits 7 and 0 set shifts SB out to the link cable, one bit
+	at a time. There's never anything on the other end, so SB fills with 1s.
+	When the last bit has gone, bit 7 clears and the serial interrupt is
+	requested. Transfers on the external clock never finish.
+
+	What's shifted out is kept in SerialOutput - test roms print to it.
+*/
+
+const (
+	serialDataAddress    uint16 = 0xFF01
+	serialControlAddress uint16 = 0xFF02
+)
+
+const (
+	serialBitCycles     = 512
+	fastSerialBitCycles = 16
+)
+
+func (c *Controller) startSerialTransfer(value byte) {
+	if !utils.IsBitSet(value, 7) || !utils.IsBitSet(value, 0) {
+		c.scheduler.Cancel(scheduler.SerialTransfer)
+		return
+	}
+	c.SerialOutput += string(c.ReadAddr(serialDataAddress))
+	bitCycles := uint64(serialBitCycles)
+	if c.model == CGB && utils.IsBitSet(value, 1) {
+		bitCycles = fastSerialBitCycles
+	}
+	c.scheduler.Schedule(scheduler.SerialTransfer, 8*bitCycles)
+}
+
+func (c *Controller) finishSerialTransfer() {
+	c.stack.WriteAddr(serialDataAddress-STACK_START, 0xFF)
+	control := c.stack.ReadAddr(serialControlAddress - STACK_START)
+	c.stack.WriteAddr(serialControlAddress-STACK_START, utils.UnsetBit(control, 7))
+	c.InterruptFlags.SerialLinkInterrupt()
+}
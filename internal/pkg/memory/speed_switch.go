@@ -0,0 +1,68 @@
+package memory
+
+import (
+	"github.com/mr-tim/goboye/internal/pkg/scheduler"
+	"github.com/mr-tim/goboye/internal/pkg/utils"
+)
+
+/*
+	KEY1 (0xFF4D) - CGB speed switch
+
+	0 - prepare a switch, which happens at the next STOP
+	7 - current speed (0: normal, 1: double) - read only
+
+	In double speed the cpu, DIV and the timer run twice as fast, while the
+	display (and real time) carry on at the normal rate.
+*/
+
+type speedSwitchRegister struct {
+	prepared    bool
+	doubleSpeed bool
+}
+
+func (r *speedSwitchRegister) Read() byte {
+	value := byte(0x7E)
+	if r.prepared {
+		value = utils.SetBit(value, 0)
+	}
+	if r.doubleSpeed {
+		value = utils.SetBit(value, 7)
+	}
+	return value
+}
+
+func (r *speedSwitchRegister) Write(value byte) {
+	r.prepared = utils.IsBitSet(value, 0)
+}
+
+func (r *speedSwitchRegister) IsPrepared() bool {
+	return r.prepared
+}
+
+func (r *speedSwitchRegister) IsDoubleSpeed() bool {
+	return r.doubleSpeed
+}
+
+// Switch swaps between normal and double speed, as STOP does when a switch
+// has been prepared.
+func (r *speedSwitchRegister) Switch() {
+	r.doubleSpeed = !r.doubleSpeed
+	r.prepared = false
+}
+
+// SwitchSpeed switches speed, as STOP does when a switch has been prepared.
+// The display keeps its rate, so what's left of its current mode takes twice
+// (or half) as many cpu cycles, and the frame sequencer moves to the other
+// counter bit.
+func (c *Controller) SwitchSpeed() {
+	c.SpeedSwitch.Switch()
+	if until, ok := c.scheduler.Until(scheduler.DisplayMode); ok {
+		if c.SpeedSwitch.IsDoubleSpeed() {
+			until *= 2
+		} else {
+			until /= 2
+		}
+		c.scheduler.Schedule(scheduler.DisplayMode, until)
+	}
+	c.scheduleFrameSequencer()
+}
@@ -1,84 +1,254 @@
 package memory
 
-import "github.com/mr-tim/goboye/internal/pkg/utils"
+import (
+	"github.com/mr-tim/goboye/internal/pkg/scheduler"
+	"github.com/mr-tim/goboye/internal/pkg/utils"
+)
+
+/*
+	Timer - DIV (0xFF04), TIMA (0xFF05), TMA (0xFF06) and TAC (0xFF07)
+
+	Everything runs off one 16 bit system counter, which goes up every cpu
+	cycle. DIV is its top byte. TAC picks one of the counter's bits, which is
+	ANDed with the enable bit, and TIMA goes up whenever that signal falls:
+
+	TAC 0-1 - clock select: 0 - bit 9 (4096Hz), 1 - bit 3 (262144Hz),
+	          2 - bit 5 (65536Hz), 3 - bit 7 (16384Hz)
+	TAC 2   - enable
 
-type divRegister struct {
-	value      byte
-	cycleCount uint16
+	So anything that drops the signal ticks TIMA - resetting DIV while the
+	selected bit is set, or switching the timer off or to another bit.
+
+	When TIMA overflows it reads 0 for an M-cycle, before being reloaded from
+	TMA and requesting the timer interrupt. Writing TIMA in that M-cycle
+	cancels the reload. During the M-cycle of the reload, TIMA writes are
+	ignored and TMA writes go straight through to TIMA too.
+*/
+
+const (
+	DividerAddress      uint16 = 0xFF04
+	TimerCounterAddress uint16 = 0xFF05
+	TimerModuloAddress  uint16 = 0xFF06
+	TimerControlAddress uint16 = 0xFF07
+)
+
+// the counter bit TIMA follows, for each clock select
+var timerBits = [4]uint16{1 << 9, 1 << 3, 1 << 5, 1 << 7}
+
+// reloadCycles is how long TIMA reads 0 after overflowing
+const reloadCycles = 4
+
+// Timer isn't stepped - it works out how far the counter has gone from the
+// master clock when it's next touched, and schedules an event for when TIMA
+// will overflow.
+type Timer struct {
+	scheduler *scheduler.Scheduler
+	counter   uint16
+	// the master clock the last time the counter was brought up to date
+	synced uint64
+	tima   byte
+	tma    byte
+	tac    byte
+	// TIMA has overflowed, and is waiting to be reloaded
+	overflowed bool
+	// when TIMA was last reloaded from TMA
+	reloadedAt  uint64
+	hasReloaded bool
 }
 
-func (r *divRegister) Read() byte {
-	return r.value
+func newTimer(s *scheduler.Scheduler) Timer {
+	return Timer{scheduler: s}
+}
+
+// Counter is the 16 bit system counter, of which DIV is the top byte.
+func (t *Timer) Counter() uint16 {
+	t.sync()
+	return t.counter
 }
 
-func (r *divRegister) Write(_value byte) {
-	// any write resets the div register to 0
-	r.value = 0x00
+func (t *Timer) isEnabled() bool {
+	return utils.IsBitSet(t.tac, 2)
 }
 
-func (r *divRegister) Update(cycles uint8) {
-	r.cycleCount += uint16(cycles)
-	if r.cycleCount > 255 {
-		r.cycleCount = 0
-		r.value += 1
+// period is how many cycles there are between falling edges of the selected
+// counter bit.
+func (t *Timer) period() uint64 {
+	return uint64(timerBits[t.tac&0x03]) * 2
+}
+
+func (t *Timer) signal() bool {
+	return t.isEnabled() && t.counter&timerBits[t.tac&0x03] != 0
+}
+
+// sync brings the counter up to the master clock, counting the falling edges
+// TIMA has seen on the way.
+func (t *Timer) sync() {
+	now := t.scheduler.Now()
+	elapsed := now - t.synced
+	t.synced = now
+	if elapsed == 0 {
+		return
+	}
+	if t.isEnabled() {
+		period := t.period()
+		start := uint64(t.counter)
+		t.count((start+elapsed)/period - start/period)
+	}
+	t.counter += uint16(elapsed)
+}
+
+func (t *Timer) count(edges uint64) {
+	if edges == 0 {
+		return
+	}
+	total := uint64(t.tima) + edges
+	if total > 0xFF {
+		t.tima = 0
+		t.overflowed = true
+		t.scheduler.Schedule(scheduler.TimerReload, reloadCycles)
+	} else {
+		t.tima = byte(total)
 	}
 }
 
-type timerController struct {
-	value           byte
-	timerCycleCount int
+// scheduleOverflow works out when TIMA will next overflow. The counter must
+// be up to date.
+func (t *Timer) scheduleOverflow() {
+	if !t.isEnabled() || t.overflowed {
+		t.scheduler.Cancel(scheduler.TimerOverflow)
+		return
+	}
+	period := t.period()
+	toEdge := period - uint64(t.counter)%period
+	t.scheduler.Schedule(scheduler.TimerOverflow, toEdge+uint64(0xFF-t.tima)*period)
 }
 
-func (c *timerController) Read() byte {
-	return c.value
+func (t *Timer) isReloading() bool {
+	return t.hasReloaded && t.scheduler.Now()-t.reloadedAt < reloadCycles
 }
 
-func (c *timerController) Write(value byte) {
-	previousClockSelect := c.GetInputClockSelect()
-	c.value = value & 0x07
-	currentClockSelect := c.GetInputClockSelect()
+// overflow handles the TimerOverflow event - catching up leaves TIMA at 0,
+// waiting to be reloaded.
+func (t *Timer) overflow() {
+	t.sync()
+	t.scheduleOverflow()
+}
 
-	if previousClockSelect != currentClockSelect {
-		c.timerCycleCount = currentClockSelect.Countdown()
+// reload handles the TimerReload event, returning whether the timer
+// interrupt should be requested.
+func (t *Timer) reload() bool {
+	t.sync()
+	if !t.overflowed {
+		return false
 	}
+	t.overflowed = false
+	t.tima = t.tma
+	t.reloadedAt = t.scheduler.Now()
+	t.hasReloaded = true
+	t.scheduleOverflow()
+	return true
 }
 
-func (c *timerController) IsStarted() bool {
-	return utils.IsBitSet(c.value, 2)
+// setCounter sets the counter outright, without any falling edges - it's
+// only for starting up.
+func (t *Timer) setCounter(counter uint16) {
+	t.sync()
+	t.counter = counter
+	t.scheduleOverflow()
 }
 
-func (c *timerController) GetInputClockSelect() ClockSelect {
-	return ClockSelect(c.value & 0x03)
+// untilFallingEdge is how many cycles until the given counter bit next
+// falls.
+func (t *Timer) untilFallingEdge(bit uint) uint64 {
+	t.sync()
+	period := uint64(2) << bit
+	return period - uint64(t.counter)%period
 }
 
-func (c *timerController) UpdateCountdown(cycles uint8) bool {
-	c.timerCycleCount -= int(cycles)
-	trigger := c.timerCycleCount <= 0
-	if trigger {
-		c.timerCycleCount = c.GetInputClockSelect().Countdown()
+func (t *Timer) isCounterBitSet(bit uint) bool {
+	t.sync()
+	return t.counter&(1<<bit) != 0
+}
+
+func (t *Timer) ReadDivider() byte {
+	t.sync()
+	return byte(t.counter >> 8)
+}
+
+// resetDivider clears the counter. That's a falling edge for TIMA if its bit
+// was set.
+func (t *Timer) resetDivider() {
+	t.sync()
+	if t.signal() {
+		t.count(1)
 	}
-	return trigger
+	t.counter = 0
+	t.scheduleOverflow()
 }
 
-type ClockSelect byte
+// the DIV register needs the controller, as resetting the counter affects the
+// frame sequencer too
+type divRegister Controller
 
-var (
-	Clock4k   ClockSelect = 0
-	Clock262k ClockSelect = 1
-	Clock65k  ClockSelect = 2
-	Clock16k  ClockSelect = 3
-)
+func (r *divRegister) Read() byte {
+	return r.Timer.ReadDivider()
+}
+
+func (r *divRegister) Write(_ byte) {
+	(*Controller)(r).ResetDivider()
+}
+
+type timaRegister Timer
+
+func (r *timaRegister) Read() byte {
+	(*Timer)(r).sync()
+	return r.tima
+}
+
+func (r *timaRegister) Write(value byte) {
+	t := (*Timer)(r)
+	t.sync()
+	if t.isReloading() {
+		return
+	}
+	if t.overflowed {
+		// writing during the delay cancels the reload
+		t.overflowed = false
+		t.scheduler.Cancel(scheduler.TimerReload)
+	}
+	t.tima = value
+	t.scheduleOverflow()
+}
+
+type tmaRegister Timer
+
+func (r *tmaRegister) Read() byte {
+	return r.tma
+}
+
+func (r *tmaRegister) Write(value byte) {
+	t := (*Timer)(r)
+	t.sync()
+	t.tma = value
+	if t.isReloading() {
+		t.tima = value
+		t.scheduleOverflow()
+	}
+}
+
+type tacRegister Timer
+
+func (r *tacRegister) Read() byte {
+	return 0xF8 | r.tac
+}
 
-func (s ClockSelect) Countdown() int {
-	switch s {
-	case Clock4k:
-		return 1024
-	case Clock262k:
-		return 16
-	case Clock65k:
-		return 64
-	case Clock16k:
-		return 256
+func (r *tacRegister) Write(value byte) {
+	t := (*Timer)(r)
+	t.sync()
+	before := t.signal()
+	t.tac = value & 0x07
+	if before && !t.signal() {
+		t.count(1)
 	}
-	panic("Unsupported clock select!")
+	t.scheduleOverflow()
 }
@@ -0,0 +1,96 @@
+package memory
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestTimerCountsOnFallingEdge(t *testing.T) {
+	c := NewController()
+	// bit 3 - every 16 cycles
+	c.WriteAddr(TimerControlAddress, 0x05)
+	advance(&c, 12)
+	assert.Equal(t, uint8(0x00), c.ReadAddr(TimerCounterAddress))
+	advance(&c, 4)
+	assert.Equal(t, uint8(0x01), c.ReadAddr(TimerCounterAddress))
+	advance(&c, 32)
+	assert.Equal(t, uint8(0x03), c.ReadAddr(TimerCounterAddress))
+
+	advance(&c, 208)
+	assert.Equal(t, uint8(0x01), c.ReadAddr(DividerAddress))
+	assert.Equal(t, uint8(0xFD), c.ReadAddr(TimerControlAddress))
+}
+
+func TestDividerResetGlitch(t *testing.T) {
+	c := NewController()
+	c.WriteAddr(TimerControlAddress, 0x05)
+	advance(&c, 8)
+	// bit 3 is set, so clearing the counter is a falling edge
+	c.WriteAddr(DividerAddress, 0x12)
+	assert.Equal(t, uint8(0x01), c.ReadAddr(TimerCounterAddress))
+	assert.Equal(t, uint16(0), c.Timer.Counter())
+
+	advance(&c, 4)
+	c.WriteAddr(DividerAddress, 0x00)
+	assert.Equal(t, uint8(0x01), c.ReadAddr(TimerCounterAddress))
+}
+
+func TestTimerControlGlitch(t *testing.T) {
+	c := NewController()
+	c.WriteAddr(TimerControlAddress, 0x05)
+	advance(&c, 8)
+	// stopping the timer while the bit is set ticks TIMA
+	c.WriteAddr(TimerControlAddress, 0x01)
+	assert.Equal(t, uint8(0x01), c.ReadAddr(TimerCounterAddress))
+
+	// as does switching to a bit that's clear
+	c.WriteAddr(TimerControlAddress, 0x05)
+	c.WriteAddr(TimerControlAddress, 0x06)
+	assert.Equal(t, uint8(0x02), c.ReadAddr(TimerCounterAddress))
+
+	advance(&c, 64)
+	assert.Equal(t, uint8(0x03), c.ReadAddr(TimerCounterAddress))
+}
+
+func TestTimerOverflowReload(t *testing.T) {
+	c := NewController()
+	c.WriteAddr(TimerModuloAddress, 0x80)
+	c.WriteAddr(TimerCounterAddress, 0xFF)
+	c.WriteAddr(TimerControlAddress, 0x05)
+
+	advance(&c, 16)
+	// TIMA reads 0 for an M-cycle before the reload
+	assert.Equal(t, uint8(0x00), c.ReadAddr(TimerCounterAddress))
+	assert.False(t, c.InterruptFlags.TimerOverflow())
+
+	advance(&c, 4)
+	assert.Equal(t, uint8(0x80), c.ReadAddr(TimerCounterAddress))
+	assert.True(t, c.InterruptFlags.TimerOverflow())
+
+	// TIMA writes are ignored while it's being reloaded, but TMA's go through
+	c.WriteAddr(TimerCounterAddress, 0x10)
+	assert.Equal(t, uint8(0x80), c.ReadAddr(TimerCounterAddress))
+	c.WriteAddr(TimerModuloAddress, 0x90)
+	assert.Equal(t, uint8(0x90), c.ReadAddr(TimerCounterAddress))
+
+	advance(&c, 4)
+	c.WriteAddr(TimerCounterAddress, 0x10)
+	assert.Equal(t, uint8(0x10), c.ReadAddr(TimerCounterAddress))
+}
+
+func TestTimerOverflowCancelledByWrite(t *testing.T) {
+	c := NewController()
+	c.WriteAddr(TimerModuloAddress, 0x80)
+	c.WriteAddr(TimerCounterAddress, 0xFF)
+	c.WriteAddr(TimerControlAddress, 0x05)
+
+	advance(&c, 16)
+	c.WriteAddr(TimerCounterAddress, 0x42)
+	advance(&c, 4)
+	assert.Equal(t, uint8(0x42), c.ReadAddr(TimerCounterAddress))
+	assert.False(t, c.InterruptFlags.TimerOverflow())
+}
+
+func advance(c *Controller, cycles uint64) {
+	c.Scheduler().Advance(cycles, c)
+}
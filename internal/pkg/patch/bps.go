@@ -0,0 +1,120 @@
+package patch
+
+import "fmt"
+
+/*
+	BPS body
+
+	varint - source size
+	varint - target size
+	varint - metadata size, followed by that many bytes of metadata
+	actions, until the footer:
+		varint - low 2 bits are the action, the rest are length-1
+		0: source read - copy from the source at the current output position
+		1: target read - copy from the patch
+		2: source copy - varint relative move of the source pointer, then copy
+		3: target copy - varint relative move of the target pointer, then copy
+			from the output so far (which may overlap what's being written)
+
+	Relative moves are sign and magnitude, with the sign in the low bit.
+*/
+
+const (
+	bpsSourceRead = iota
+	bpsTargetRead
+	bpsSourceCopy
+	bpsTargetCopy
+)
+
+func applyBps(rom []byte, p []byte) ([]byte, error) {
+	r, f, err := splitFooter(p, bpsMagic)
+	if err != nil {
+		return nil, err
+	}
+	sourceSize, err := r.varint()
+	if err != nil {
+		return nil, err
+	}
+	targetSize, err := r.varint()
+	if err != nil {
+		return nil, err
+	}
+	metadataSize, err := r.varint()
+	if err != nil {
+		return nil, err
+	}
+	if _, err = r.bytes(metadataSize); err != nil {
+		return nil, err
+	}
+	if sourceSize != len(rom) {
+		return nil, fmt.Errorf("rom is %d bytes, patch expects %d", len(rom), sourceSize)
+	}
+	if err = f.checkSource(rom); err != nil {
+		return nil, err
+	}
+
+	out := make([]byte, targetSize)
+	outPos, sourcePos, targetPos := 0, 0, 0
+	for !r.done() {
+		data, err := r.varint()
+		if err != nil {
+			return nil, err
+		}
+		action, length := data&0x03, data>>2+1
+		if outPos+length > targetSize {
+			return nil, fmt.Errorf("patch writes past the end of the %d byte target", targetSize)
+		}
+
+		switch action {
+		case bpsSourceRead:
+			if outPos+length > len(rom) {
+				return nil, fmt.Errorf("patch reads past the end of the %d byte rom", len(rom))
+			}
+			copy(out[outPos:], rom[outPos:outPos+length])
+		case bpsTargetRead:
+			b, err := r.bytes(length)
+			if err != nil {
+				return nil, err
+			}
+			copy(out[outPos:], b)
+		case bpsSourceCopy:
+			if sourcePos, err = bpsMove(r, sourcePos); err != nil {
+				return nil, err
+			}
+			if sourcePos < 0 || sourcePos+length > len(rom) {
+				return nil, fmt.Errorf("patch reads past the end of the %d byte rom", len(rom))
+			}
+			copy(out[outPos:], rom[sourcePos:sourcePos+length])
+			sourcePos += length
+		case bpsTargetCopy:
+			if targetPos, err = bpsMove(r, targetPos); err != nil {
+				return nil, err
+			}
+			if targetPos < 0 || targetPos >= outPos {
+				return nil, fmt.Errorf("patch copies from outside the target at %d", targetPos)
+			}
+			// byte at a time, as the copy can overlap the bytes it produces
+			for i := 0; i < length; i++ {
+				out[outPos+i] = out[targetPos]
+				targetPos += 1
+			}
+		}
+		outPos += length
+	}
+
+	if err = f.checkTarget(out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func bpsMove(r *reader, pos int) (int, error) {
+	d, err := r.varint()
+	if err != nil {
+		return 0, err
+	}
+	if d&1 != 0 {
+		return pos - d>>1, nil
+	}
+	return pos + d>>1, nil
+}
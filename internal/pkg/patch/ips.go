@@ -0,0 +1,78 @@
+package patch
+
+import "bytes"
+
+/*
+	IPS records - all values big endian
+
+	3 bytes - offset
+	2 bytes - length, followed by that many bytes of data
+		or, if 0: 2 bytes of run length, then 1 byte to repeat
+
+	Records end with "EOF", which may be followed by a 3 byte size to
+	truncate the rom to.
+*/
+
+var ipsEof = []byte("EOF")
+
+func applyIps(rom []byte, p []byte) ([]byte, error) {
+	out := make([]byte, len(rom))
+	copy(out, rom)
+
+	r := &reader{data: p, pos: len(ipsMagic)}
+	for {
+		if bytes.HasPrefix(r.data[r.pos:], ipsEof) {
+			r.pos += len(ipsEof)
+			break
+		}
+		offset, err := r.bytes(3)
+		if err != nil {
+			return nil, err
+		}
+		size, err := r.bytes(2)
+		if err != nil {
+			return nil, err
+		}
+		data, err := ipsRecordData(r, int(size[0])<<8|int(size[1]))
+		if err != nil {
+			return nil, err
+		}
+		out = writeAt(out, int(offset[0])<<16|int(offset[1])<<8|int(offset[2]), data)
+	}
+
+	if len(r.data)-r.pos == 3 {
+		size, _ := r.bytes(3)
+		if truncate := int(size[0])<<16 | int(size[1])<<8 | int(size[2]); truncate < len(out) {
+			out = out[:truncate]
+		}
+	}
+	return out, nil
+}
+
+func ipsRecordData(r *reader, size int) ([]byte, error) {
+	if size != 0 {
+		return r.bytes(size)
+	}
+	run, err := r.bytes(2)
+	if err != nil {
+		return nil, err
+	}
+	value, err := r.byte()
+	if err != nil {
+		return nil, err
+	}
+	data := make([]byte, int(run[0])<<8|int(run[1]))
+	for i := range data {
+		data[i] = value
+	}
+	return data, nil
+}
+
+// writeAt copies data into out at offset, growing out if it's too short.
+func writeAt(out []byte, offset int, data []byte) []byte {
+	if end := offset + len(data); end > len(out) {
+		out = append(out, make([]byte, end-len(out))...)
+	}
+	copy(out[offset:], data)
+	return out
+}
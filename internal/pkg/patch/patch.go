@@ -0,0 +1,174 @@
+package patch
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+)
+
+/*
+	Soft patches are applied to the rom image as it's loaded, so the original
+	file is never modified.
+
+	IPS - "PATCH", then records of offset/length/data, ending with "EOF"
+	UPS - "UPS1", then runs of bytes xor'd with the source, with crc32s of
+		the source, target and patch in a 12 byte footer
+	BPS - "BPS1", then copy/read actions building the target, with the same
+		footer as UPS
+*/
+
+type Format int
+
+const (
+	IPS Format = iota
+	UPS
+	BPS
+)
+
+func (f Format) String() string {
+	switch f {
+	case IPS:
+		return "IPS"
+	case UPS:
+		return "UPS"
+	case BPS:
+		return "BPS"
+	}
+	return fmt.Sprintf("Format(%d)", int(f))
+}
+
+// Extensions are the file extensions of each patch format, in the order
+// they're searched for next to a rom.
+var Extensions = []string{".ips", ".ups", ".bps"}
+
+var ErrUnknownFormat = errors.New("not an IPS, UPS or BPS patch")
+var ErrTruncated = errors.New("patch is truncated")
+
+var ipsMagic = []byte("PATCH")
+var upsMagic = []byte("UPS1")
+var bpsMagic = []byte("BPS1")
+
+// Detect works out the format of a patch from its header.
+func Detect(p []byte) (Format, error) {
+	switch {
+	case bytes.HasPrefix(p, ipsMagic):
+		return IPS, nil
+	case bytes.HasPrefix(p, upsMagic):
+		return UPS, nil
+	case bytes.HasPrefix(p, bpsMagic):
+		return BPS, nil
+	default:
+		return 0, ErrUnknownFormat
+	}
+}
+
+// Apply patches a rom, returning the patched copy. The rom itself is left
+// unchanged. UPS and BPS checksums are verified against both the rom and the
+// result.
+func Apply(rom []byte, p []byte) ([]byte, error) {
+	format, err := Detect(p)
+	if err != nil {
+		return nil, err
+	}
+	switch format {
+	case IPS:
+		return applyIps(rom, p)
+	case UPS:
+		return applyUps(rom, p)
+	default:
+		return applyBps(rom, p)
+	}
+}
+
+// reader walks through the body of a patch.
+type reader struct {
+	data []byte
+	pos  int
+}
+
+func (r *reader) done() bool {
+	return r.pos >= len(r.data)
+}
+
+func (r *reader) byte() (byte, error) {
+	if r.done() {
+		return 0, ErrTruncated
+	}
+	b := r.data[r.pos]
+	r.pos += 1
+	return b, nil
+}
+
+func (r *reader) bytes(n int) ([]byte, error) {
+	if n < 0 || len(r.data)-r.pos < n {
+		return nil, ErrTruncated
+	}
+	b := r.data[r.pos : r.pos+n]
+	r.pos += n
+	return b, nil
+}
+
+// varint reads the variable length numbers used by UPS and BPS. Each byte
+// holds 7 bits, with the top bit marking the last byte. Every byte but the
+// last also adds one, so there's only a single encoding for each value.
+func (r *reader) varint() (int, error) {
+	value, shift := 0, 1
+	for {
+		x, err := r.byte()
+		if err != nil {
+			return 0, err
+		}
+		value += int(x&0x7F) * shift
+		if x&0x80 != 0 {
+			return value, nil
+		}
+		shift <<= 7
+		value += shift
+		if shift > 1<<42 {
+			return 0, errors.New("patch number is too large")
+		}
+	}
+}
+
+// footer is the crc32s at the end of UPS and BPS patches.
+type footer struct {
+	source uint32
+	target uint32
+	patch  uint32
+}
+
+const footerSize = 12
+
+// splitFooter separates a UPS or BPS patch into its body (after the magic
+// number) and checksum footer, checking the patch's own checksum.
+func splitFooter(p []byte, magic []byte) (*reader, footer, error) {
+	if len(p) < len(magic)+footerSize {
+		return nil, footer{}, ErrTruncated
+	}
+	end := len(p) - footerSize
+	f := footer{
+		source: binary.LittleEndian.Uint32(p[end:]),
+		target: binary.LittleEndian.Uint32(p[end+4:]),
+		patch:  binary.LittleEndian.Uint32(p[end+8:]),
+	}
+	if actual := crc32.ChecksumIEEE(p[:len(p)-4]); actual != f.patch {
+		return nil, f, fmt.Errorf("patch checksum is %08X, expected %08X - the patch file is damaged", actual, f.patch)
+	}
+	return &reader{data: p[len(magic):end]}, f, nil
+}
+
+func (f footer) checkSource(rom []byte) error {
+	if actual := crc32.ChecksumIEEE(rom); actual != f.source {
+		return fmt.Errorf("rom checksum is %08X, expected %08X - the patch is for a different rom", actual, f.source)
+	}
+	return nil
+}
+
+func (f footer) checkTarget(result []byte) error {
+	if actual := crc32.ChecksumIEEE(result); actual != f.target {
+		return fmt.Errorf("patched rom checksum is %08X, expected %08X", actual, f.target)
+	}
+	return nil
+}
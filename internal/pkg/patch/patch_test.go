@@ -0,0 +1,136 @@
+package patch
+
+import (
+	"encoding/binary"
+	"github.com/stretchr/testify/assert"
+	"hash/crc32"
+	"testing"
+)
+
+func encodeVarint(v int) []byte {
+	var out []byte
+	for {
+		x := byte(v & 0x7F)
+		v >>= 7
+		if v == 0 {
+			return append(out, 0x80|x)
+		}
+		out = append(out, x)
+		v -= 1
+	}
+}
+
+func withFooter(body []byte, source, target []byte) []byte {
+	p := make([]byte, len(body)+footerSize)
+	copy(p, body)
+	binary.LittleEndian.PutUint32(p[len(body):], crc32.ChecksumIEEE(source))
+	binary.LittleEndian.PutUint32(p[len(body)+4:], crc32.ChecksumIEEE(target))
+	binary.LittleEndian.PutUint32(p[len(body)+8:], crc32.ChecksumIEEE(p[:len(p)-4]))
+	return p
+}
+
+func TestVarintRoundTrip(t *testing.T) {
+	for _, v := range []int{0, 1, 0x7F, 0x80, 0x3FFF, 0x4080, 0x123456} {
+		r := &reader{data: encodeVarint(v)}
+		decoded, err := r.varint()
+		assert.Nil(t, err)
+		assert.Equal(t, v, decoded)
+		assert.True(t, r.done())
+	}
+}
+
+func TestDetect(t *testing.T) {
+	f, err := Detect([]byte("PATCHEOF"))
+	assert.Nil(t, err)
+	assert.Equal(t, IPS, f)
+
+	_, err = Detect([]byte{0x00, 0x01})
+	assert.Equal(t, ErrUnknownFormat, err)
+}
+
+func TestApplyIps(t *testing.T) {
+	rom := []byte{0, 1, 2, 3, 4, 5}
+	p := []byte("PATCH")
+	p = append(p, 0x00, 0x00, 0x01, 0x00, 0x02, 0xAA, 0xBB)
+	// rle record
+	p = append(p, 0x00, 0x00, 0x04, 0x00, 0x00, 0x00, 0x04, 0xCC)
+	p = append(p, []byte("EOF")...)
+
+	out, err := Apply(rom, p)
+	assert.Nil(t, err)
+	assert.Equal(t, []byte{0, 0xAA, 0xBB, 3, 0xCC, 0xCC, 0xCC, 0xCC}, out)
+	// the original is left alone
+	assert.Equal(t, []byte{0, 1, 2, 3, 4, 5}, rom)
+}
+
+func TestApplyIpsTruncates(t *testing.T) {
+	p := append([]byte("PATCHEOF"), 0x00, 0x00, 0x02)
+	out, err := Apply([]byte{0, 1, 2, 3}, p)
+	assert.Nil(t, err)
+	assert.Equal(t, []byte{0, 1}, out)
+}
+
+func TestApplyIpsTruncated(t *testing.T) {
+	_, err := Apply([]byte{0, 1}, []byte("PATCH\x00\x00\x01\x00\x05\xAA"))
+	assert.Equal(t, ErrTruncated, err)
+}
+
+func TestApplyUps(t *testing.T) {
+	rom := []byte{0, 1, 2, 3, 4, 5}
+	target := []byte{0, 1, 0xF2, 3, 4, 5, 0, 0x77}
+
+	body := append([]byte("UPS1"), encodeVarint(len(rom))...)
+	body = append(body, encodeVarint(len(target))...)
+	body = append(body, encodeVarint(2)...)
+	body = append(body, 0xF0, 0x00)
+	// the 0x00 ending the last hunk skips a byte too
+	body = append(body, encodeVarint(3)...)
+	body = append(body, 0x77, 0x00)
+	p := withFooter(body, rom, target)
+
+	out, err := Apply(rom, p)
+	assert.Nil(t, err)
+	assert.Equal(t, target, out)
+
+	_, err = Apply([]byte{9, 1, 2, 3, 4, 5}, p)
+	assert.ErrorContains(t, err, "different rom")
+}
+
+func TestApplyBps(t *testing.T) {
+	rom := []byte("abcdefgh")
+	target := []byte("abcXYZXYZXefgh")
+
+	body := append([]byte("BPS1"), encodeVarint(len(rom))...)
+	body = append(body, encodeVarint(len(target))...)
+	body = append(body, encodeVarint(2)...)
+	body = append(body, "{}"...)
+	// source read "abc"
+	body = append(body, encodeVarint((3-1)<<2|bpsSourceRead)...)
+	// target read "XYZ"
+	body = append(body, encodeVarint((3-1)<<2|bpsTargetRead)...)
+	body = append(body, "XYZ"...)
+	// target copy "XYZX" from offset 3, overlapping the bytes it writes
+	body = append(body, encodeVarint((4-1)<<2|bpsTargetCopy)...)
+	body = append(body, encodeVarint(3<<1)...)
+	// source copy "efgh" from offset 4
+	body = append(body, encodeVarint((4-1)<<2|bpsSourceCopy)...)
+	body = append(body, encodeVarint(4<<1)...)
+	p := withFooter(body, rom, target)
+
+	out, err := Apply(rom, p)
+	assert.Nil(t, err)
+	assert.Equal(t, target, out)
+}
+
+func TestApplyBpsDamagedPatch(t *testing.T) {
+	rom := []byte("abcd")
+	body := append([]byte("BPS1"), encodeVarint(4)...)
+	body = append(body, encodeVarint(4)...)
+	body = append(body, encodeVarint(0)...)
+	body = append(body, encodeVarint((4-1)<<2|bpsSourceRead)...)
+	p := withFooter(body, rom, rom)
+
+	p[len(p)-13] ^= 0x01
+	_, err := Apply(rom, p)
+	assert.ErrorContains(t, err, "damaged")
+}
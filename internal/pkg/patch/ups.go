@@ -0,0 +1,65 @@
+package patch
+
+import "fmt"
+
+/*
+	UPS body
+
+	varint - source size
+	varint - target size
+	hunks, until the footer:
+		varint - bytes to skip
+		bytes to xor with the source, ending with 0x00 (which also skips a byte)
+*/
+
+func applyUps(rom []byte, p []byte) ([]byte, error) {
+	r, f, err := splitFooter(p, upsMagic)
+	if err != nil {
+		return nil, err
+	}
+	sourceSize, err := r.varint()
+	if err != nil {
+		return nil, err
+	}
+	targetSize, err := r.varint()
+	if err != nil {
+		return nil, err
+	}
+	if sourceSize != len(rom) {
+		return nil, fmt.Errorf("rom is %d bytes, patch expects %d", len(rom), sourceSize)
+	}
+	if err = f.checkSource(rom); err != nil {
+		return nil, err
+	}
+
+	out := make([]byte, targetSize)
+	copy(out, rom)
+	pos := 0
+	for !r.done() {
+		skip, err := r.varint()
+		if err != nil {
+			return nil, err
+		}
+		pos += skip
+		for {
+			x, err := r.byte()
+			if err != nil {
+				return nil, err
+			}
+			if x == 0 {
+				pos += 1
+				break
+			}
+			if pos >= targetSize {
+				return nil, fmt.Errorf("patch writes past the end of the %d byte target", targetSize)
+			}
+			out[pos] ^= x
+			pos += 1
+		}
+	}
+
+	if err = f.checkTarget(out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
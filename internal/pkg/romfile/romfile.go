@@ -0,0 +1,106 @@
+package romfile
+
+import (
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"strings"
+)
+
+/*
+	Roms can be loaded as plain images, or from inside an archive:
+		.zip - the first .gb/.gbc entry, or the one named after a '#',
+			eg: roms.zip#tetris.gb
+		.gz  - a single gzipped rom image
+
+	Archives are detected from their contents rather than their file names,
+	so readers with no name work too.
+*/
+
+var ErrNoRomInArchive = errors.New("no .gb or .gbc file found in archive")
+
+var zipMagic = []byte{'P', 'K', 0x03, 0x04}
+var gzipMagic = []byte{0x1f, 0x8b}
+
+const entrySeparator = "#"
+
+// SplitPath splits a path like roms.zip#tetris.gb into the file to open and
+// the entry to read from it. Entry is empty if no entry was named.
+func SplitPath(p string) (file string, entry string) {
+	idx := strings.LastIndex(p, entrySeparator)
+	if idx < 0 || !strings.EqualFold(path.Ext(p[:idx]), ".zip") {
+		return p, ""
+	}
+	return p[:idx], p[idx+1:]
+}
+
+// Open reads a rom from a file, unpacking it if it's an archive.
+func Open(p string) ([]byte, error) {
+	file, entry := SplitPath(p)
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return Read(f, entry)
+}
+
+// Read reads a rom from r, unpacking it if it's an archive. For zip archives,
+// entry names the file to read - if it's empty, the first rom is used.
+func Read(r io.Reader, entry string) ([]byte, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case bytes.HasPrefix(data, zipMagic):
+		return readZip(data, entry)
+	case bytes.HasPrefix(data, gzipMagic):
+		return readGzip(data)
+	default:
+		return data, nil
+	}
+}
+
+func readZip(data []byte, entry string) ([]byte, error) {
+	z, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, err
+	}
+
+	for _, f := range z.File {
+		if entry == "" && isRomName(f.Name) || entry != "" && f.Name == entry {
+			rc, err := f.Open()
+			if err != nil {
+				return nil, err
+			}
+			defer rc.Close()
+			return io.ReadAll(rc)
+		}
+	}
+
+	if entry != "" {
+		return nil, fmt.Errorf("%s not found in archive", entry)
+	}
+	return nil, ErrNoRomInArchive
+}
+
+func readGzip(data []byte) ([]byte, error) {
+	gz, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	defer gz.Close()
+	return io.ReadAll(gz)
+}
+
+func isRomName(name string) bool {
+	ext := strings.ToLower(path.Ext(name))
+	return ext == ".gb" || ext == ".gbc"
+}
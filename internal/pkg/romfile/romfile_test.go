@@ -0,0 +1,82 @@
+package romfile
+
+import (
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func zipOf(t *testing.T, files map[string][]byte, order []string) []byte {
+	b := new(bytes.Buffer)
+	z := zip.NewWriter(b)
+	for _, name := range order {
+		w, err := z.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		w.Write(files[name])
+	}
+	z.Close()
+	return b.Bytes()
+}
+
+func TestReadPlainRom(t *testing.T) {
+	rom, err := Read(bytes.NewReader([]byte{0x01, 0x02, 0x03}), "")
+	assert.Nil(t, err)
+	assert.Equal(t, []byte{0x01, 0x02, 0x03}, rom)
+}
+
+func TestReadGzip(t *testing.T) {
+	b := new(bytes.Buffer)
+	gz := gzip.NewWriter(b)
+	gz.Write([]byte{0x01, 0x02, 0x03})
+	gz.Close()
+
+	rom, err := Read(b, "")
+	assert.Nil(t, err)
+	assert.Equal(t, []byte{0x01, 0x02, 0x03}, rom)
+}
+
+func TestReadZipPicksFirstRom(t *testing.T) {
+	files := map[string][]byte{
+		"readme.txt": {0xFF},
+		"a.GB":       {0x01},
+		"b.gbc":      {0x02},
+	}
+	data := zipOf(t, files, []string{"readme.txt", "a.GB", "b.gbc"})
+
+	rom, err := Read(bytes.NewReader(data), "")
+	assert.Nil(t, err)
+	assert.Equal(t, []byte{0x01}, rom)
+
+	rom, err = Read(bytes.NewReader(data), "b.gbc")
+	assert.Nil(t, err)
+	assert.Equal(t, []byte{0x02}, rom)
+
+	_, err = Read(bytes.NewReader(data), "c.gb")
+	assert.NotNil(t, err)
+}
+
+func TestReadZipWithoutRom(t *testing.T) {
+	data := zipOf(t, map[string][]byte{"readme.txt": {0xFF}}, []string{"readme.txt"})
+
+	_, err := Read(bytes.NewReader(data), "")
+	assert.Equal(t, ErrNoRomInArchive, err)
+}
+
+func TestSplitPath(t *testing.T) {
+	file, entry := SplitPath("roms/all.zip#tetris.gb")
+	assert.Equal(t, "roms/all.zip", file)
+	assert.Equal(t, "tetris.gb", entry)
+
+	file, entry = SplitPath("roms/all.ZIP")
+	assert.Equal(t, "roms/all.ZIP", file)
+	assert.Equal(t, "", entry)
+
+	// only zips have entries
+	file, entry = SplitPath("roms/#1 hit.gb")
+	assert.Equal(t, "roms/#1 hit.gb", file)
+	assert.Equal(t, "", entry)
+}
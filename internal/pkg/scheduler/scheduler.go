@@ -0,0 +1,128 @@
+package scheduler
+
+import "math"
+
+// Event is something the hardware does at a known time, like the display
+// changing mode. Each event is either pending, at one time, or not.
+type Event byte
+
+const (
+	DisplayMode Event = iota
+	TimerOverflow
+	TimerReload
+	SerialTransfer
+	DmaEnd
+	FrameSequencer
+	eventCount
+)
+
+var eventNames = [eventCount]string{
+	"DisplayMode",
+	"TimerOverflow",
+	"TimerReload",
+	"SerialTransfer",
+	"DmaEnd",
+	"FrameSequencer",
+}
+
+func (e Event) String() string {
+	if e < eventCount {
+		return eventNames[e]
+	}
+	return "Unknown"
+}
+
+// Handler is called as each event falls due, with the clock at the event's
+// time.
+type Handler interface {
+	HandleEvent(event Event)
+}
+
+const never = math.MaxUint64
+
+// Scheduler keeps the master clock - cpu cycles since power on - and when
+// each pending event is due. Rather than every component being polled after
+// each instruction, they schedule the next time they have something to do.
+type Scheduler struct {
+	now  uint64
+	at   [eventCount]uint64
+	next uint64
+}
+
+func New() *Scheduler {
+	s := &Scheduler{}
+	for i := range s.at {
+		s.at[i] = never
+	}
+	s.next = never
+	return s
+}
+
+// Now is the master clock, in cpu cycles.
+func (s *Scheduler) Now() uint64 {
+	return s.now
+}
+
+// Schedule makes event due in cycles cycles, replacing any time it was
+// already due.
+func (s *Scheduler) Schedule(event Event, cycles uint64) {
+	s.at[event] = s.now + cycles
+	if s.at[event] < s.next {
+		s.next = s.at[event]
+	} else {
+		s.updateNext()
+	}
+}
+
+func (s *Scheduler) Cancel(event Event) {
+	if s.at[event] == never {
+		return
+	}
+	s.at[event] = never
+	s.updateNext()
+}
+
+func (s *Scheduler) IsScheduled(event Event) bool {
+	return s.at[event] != never
+}
+
+// Until is how many cycles until event is due, or false if it isn't pending.
+func (s *Scheduler) Until(event Event) (uint64, bool) {
+	if s.at[event] == never {
+		return 0, false
+	}
+	return s.at[event] - s.now, true
+}
+
+// Advance moves the clock on by cycles, handing each event that falls due to
+// h in time order. Events due at the same time run in the order they're
+// declared.
+func (s *Scheduler) Advance(cycles uint64, h Handler) {
+	target := s.now + cycles
+	for s.next <= target {
+		event := s.due()
+		s.now = s.at[event]
+		s.at[event] = never
+		s.updateNext()
+		h.HandleEvent(event)
+	}
+	s.now = target
+}
+
+func (s *Scheduler) due() Event {
+	for e, at := range s.at {
+		if at == s.next {
+			return Event(e)
+		}
+	}
+	panic("no event is due")
+}
+
+func (s *Scheduler) updateNext() {
+	s.next = never
+	for _, at := range s.at {
+		if at < s.next {
+			s.next = at
+		}
+	}
+}
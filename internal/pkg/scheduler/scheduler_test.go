@@ -0,0 +1,71 @@
+package scheduler
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+type recordingHandler struct {
+	s      *Scheduler
+	events []Event
+	times  []uint64
+}
+
+func (h *recordingHandler) HandleEvent(event Event) {
+	h.events = append(h.events, event)
+	h.times = append(h.times, h.s.Now())
+}
+
+func TestEventsRunInTimeOrder(t *testing.T) {
+	s := New()
+	h := &recordingHandler{s: s}
+	s.Schedule(SerialTransfer, 20)
+	s.Schedule(DisplayMode, 8)
+	s.Schedule(DmaEnd, 8)
+	s.Schedule(TimerOverflow, 100)
+
+	s.Advance(24, h)
+	assert.Equal(t, []Event{DisplayMode, DmaEnd, SerialTransfer}, h.events)
+	assert.Equal(t, []uint64{8, 8, 20}, h.times)
+	assert.Equal(t, uint64(24), s.Now())
+
+	until, ok := s.Until(TimerOverflow)
+	assert.True(t, ok)
+	assert.Equal(t, uint64(76), until)
+	assert.False(t, s.IsScheduled(DisplayMode))
+}
+
+func TestRescheduleAndCancel(t *testing.T) {
+	s := New()
+	h := &recordingHandler{s: s}
+	s.Schedule(TimerReload, 4)
+	s.Schedule(TimerReload, 12)
+	s.Schedule(FrameSequencer, 8)
+	s.Cancel(FrameSequencer)
+
+	s.Advance(8, h)
+	assert.Empty(t, h.events)
+	s.Advance(4, h)
+	assert.Equal(t, []Event{TimerReload}, h.events)
+}
+
+// rescheduler keeps an event running at a fixed interval, as the display does.
+type rescheduler struct {
+	s     *Scheduler
+	count int
+}
+
+func (r *rescheduler) HandleEvent(event Event) {
+	r.count++
+	r.s.Schedule(event, 10)
+}
+
+func TestEventsCanRescheduleThemselves(t *testing.T) {
+	s := New()
+	r := &rescheduler{s: s}
+	s.Schedule(DisplayMode, 10)
+	s.Advance(95, r)
+	assert.Equal(t, 9, r.count)
+	until, _ := s.Until(DisplayMode)
+	assert.Equal(t, uint64(5), until)
+}
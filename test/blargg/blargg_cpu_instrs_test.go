@@ -7,6 +7,8 @@ import (
 	"fmt"
 	"github.com/mr-tim/goboye/internal/pkg/goboye"
 	"github.com/stretchr/testify/assert"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 )
@@ -14,6 +16,9 @@ import (
 // run tests with eg:
 // go test -tags=blargg ./test/blargg -args -blargg_roms=/path/to/gb-test-roms
 var blarggRomsPath = flag.String("blargg_roms", "", "Path to blargg roms")
+var skipBoot = flag.Bool("skip_boot", false, "Start the roms without running the boot rom")
+var cycleAccurate = flag.Bool("cycle_accurate", false, "Run the cpu in cycle accurate mode")
+var traceDir = flag.String("trace_dir", "", "Write a Gameboy Doctor style trace of each rom to this directory (LY reads 0x90 while tracing, to match)")
 
 func TestBlarggCpuInstrs01(t *testing.T) {
 	doBlargTest(t, "/cpu_instrs/individual/01-special.gb")
@@ -66,6 +71,18 @@ func doBlargTest(t *testing.T, rom string) {
 	pathToRom := *blarggRomsPath + rom
 
 	e := goboye.NewEmulator()
+	e.SetSkipBoot(*skipBoot)
+	e.SetCycleAccurate(*cycleAccurate)
+	if *traceDir != "" {
+		f, err := os.Create(filepath.Join(*traceDir, strings.TrimSuffix(filepath.Base(rom), ".gb")+".log"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer f.Close()
+		e.SetTrace(f)
+		e.SetDoctor(true)
+		defer e.Close()
+	}
 	e.LoadRomImage(pathToRom)
 	e.SetDebug(true)
 	e.ContinueDebugging(false)
@@ -0,0 +1,220 @@
+//go:build singlestep
+// +build singlestep
+
+package singlestep
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"github.com/mr-tim/goboye/internal/pkg/cpu"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// runs the SingleStepTests sm83 vectors (one json file per opcode) with eg:
+// go test -tags=singlestep ./test/singlestep -args -singlestep_tests=/path/to/sm83/v1
+var testsPath = flag.String("singlestep_tests", "", "Path to the sm83 json test vectors")
+var checkBus = flag.Bool("singlestep_bus", false, "Check the order of reads and writes, as well as the final state")
+
+// failuresShown is how many failing vectors are listed for each opcode.
+const failuresShown = 5
+
+type state struct {
+	PC  uint16   `json:"pc"`
+	SP  uint16   `json:"sp"`
+	A   byte     `json:"a"`
+	B   byte     `json:"b"`
+	C   byte     `json:"c"`
+	D   byte     `json:"d"`
+	E   byte     `json:"e"`
+	F   byte     `json:"f"`
+	H   byte     `json:"h"`
+	L   byte     `json:"l"`
+	IME byte     `json:"ime"`
+	IE  *byte    `json:"ie"`
+	RAM [][2]int `json:"ram"`
+}
+
+type vector struct {
+	Name    string `json:"name"`
+	Initial state  `json:"initial"`
+	Final   state  `json:"final"`
+	// each M-cycle is [address, value, activity], with nulls when the bus
+	// is idle
+	Cycles [][]interface{} `json:"cycles"`
+}
+
+// access is a read or write on the bus.
+type access struct {
+	addr  uint16
+	value byte
+	write bool
+}
+
+func (a access) String() string {
+	if a.write {
+		return fmt.Sprintf("write %02X to %04X", a.value, a.addr)
+	}
+	return fmt.Sprintf("read %02X from %04X", a.value, a.addr)
+}
+
+// flatBus is 64KiB of ram. It's also the processor's ticker, so it can tell
+// the accesses the cpu makes on the bus (which are ticked) from its peeks at
+// the interrupt registers.
+type flatBus struct {
+	mem      [0x10000]byte
+	ticked   bool
+	accesses []access
+}
+
+func (b *flatBus) Tick(cycles uint8) {
+	b.ticked = true
+}
+
+func (b *flatBus) ReadAddr(addr uint16) byte {
+	value := b.mem[addr]
+	b.record(access{addr, value, false})
+	return value
+}
+
+func (b *flatBus) WriteAddr(addr uint16, value byte) {
+	b.mem[addr] = value
+	b.record(access{addr, value, true})
+}
+
+func (b *flatBus) record(a access) {
+	if b.ticked {
+		b.accesses = append(b.accesses, a)
+		b.ticked = false
+	}
+}
+
+func TestSingleStep(t *testing.T) {
+	if *testsPath == "" {
+		t.Fatal("Path to the test vectors not specified!")
+	}
+	files, err := filepath.Glob(filepath.Join(*testsPath, "*.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) == 0 {
+		t.Fatalf("No test vectors found in %s", *testsPath)
+	}
+
+	for _, file := range files {
+		file := file
+		t.Run(strings.TrimSuffix(filepath.Base(file), ".json"), func(t *testing.T) {
+			t.Parallel()
+			runVectors(t, file)
+		})
+	}
+}
+
+func runVectors(t *testing.T, file string) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var vectors []vector
+	if err := json.Unmarshal(data, &vectors); err != nil {
+		t.Fatalf("Unable to parse %s: %s", file, err)
+	}
+
+	failures := make([]string, 0)
+	for _, v := range vectors {
+		if problems := runVector(v); len(problems) > 0 {
+			failures = append(failures, fmt.Sprintf("%s: %s", v.Name, strings.Join(problems, ", ")))
+		}
+	}
+	if len(failures) > 0 {
+		shown := failures
+		if len(shown) > failuresShown {
+			shown = shown[:failuresShown]
+		}
+		t.Errorf("%d of %d failed, eg.\n%s", len(failures), len(vectors), strings.Join(shown, "\n"))
+	}
+}
+
+// runVector runs a single instruction, returning what didn't match.
+func runVector(v vector) []string {
+	bus := &flatBus{}
+	for _, r := range v.Initial.RAM {
+		bus.mem[r[0]] = byte(r[1])
+	}
+	if v.Initial.IE != nil {
+		bus.mem[0xFFFF] = *v.Initial.IE
+	}
+
+	p := cpu.NewProcessorWithBus(bus)
+	p.SetTicker(bus)
+	setState(p, v.Initial)
+	cycles := p.DoNextInstruction()
+
+	problems := make([]string, 0)
+	check := func(name string, expected, actual interface{}) {
+		if expected != actual {
+			problems = append(problems, fmt.Sprintf("%s is %X, expected %X", name, actual, expected))
+		}
+	}
+	check("PC", v.Final.PC, p.GetRegisterPair(cpu.RegisterPairPC))
+	check("SP", v.Final.SP, p.GetRegisterPair(cpu.RegisterPairSP))
+	for _, r := range []struct {
+		names    string
+		pair     cpu.RegisterPair
+		expected [2]byte
+	}{
+		{"AF", cpu.RegisterPairAF, [2]byte{v.Final.A, v.Final.F}},
+		{"BC", cpu.RegisterPairBC, [2]byte{v.Final.B, v.Final.C}},
+		{"DE", cpu.RegisterPairDE, [2]byte{v.Final.D, v.Final.E}},
+		{"HL", cpu.RegisterPairHL, [2]byte{v.Final.H, v.Final.L}},
+	} {
+		value := p.GetRegisterPair(r.pair)
+		check(r.names[:1], r.expected[0], byte(value>>8))
+		check(r.names[1:], r.expected[1], byte(value))
+	}
+	check("IME", v.Final.IME == 1, p.InterruptsEnabled())
+	for _, r := range v.Final.RAM {
+		check(fmt.Sprintf("(%04X)", r[0]), byte(r[1]), bus.mem[r[0]])
+	}
+	check("cycles", len(v.Cycles)*4, int(cycles))
+
+	if *checkBus {
+		expected := expectedAccesses(v.Cycles)
+		if fmt.Sprint(expected) != fmt.Sprint(bus.accesses) {
+			problems = append(problems, fmt.Sprintf("bus activity was %v, expected %v", bus.accesses, expected))
+		}
+	}
+	return problems
+}
+
+func setState(p cpu.Processor, s state) {
+	p.SetRegisterPair(cpu.RegisterPairPC, s.PC)
+	p.SetRegisterPair(cpu.RegisterPairSP, s.SP)
+	p.SetRegisterPair(cpu.RegisterPairAF, uint16(s.A)<<8|uint16(s.F))
+	p.SetRegisterPair(cpu.RegisterPairBC, uint16(s.B)<<8|uint16(s.C))
+	p.SetRegisterPair(cpu.RegisterPairDE, uint16(s.D)<<8|uint16(s.E))
+	p.SetRegisterPair(cpu.RegisterPairHL, uint16(s.H)<<8|uint16(s.L))
+	p.SetInterruptsEnabled(s.IME == 1)
+}
+
+// expectedAccesses picks the reads and writes out of a vector's cycles.
+func expectedAccesses(cycles [][]interface{}) []access {
+	result := make([]access, 0)
+	for _, c := range cycles {
+		if len(c) < 3 || c[0] == nil || c[1] == nil {
+			continue
+		}
+		activity, _ := c[2].(string)
+		addr, _ := c[0].(float64)
+		value, _ := c[1].(float64)
+		if strings.Contains(activity, "w") {
+			result = append(result, access{uint16(addr), byte(value), true})
+		} else if strings.Contains(activity, "r") {
+			result = append(result, access{uint16(addr), byte(value), false})
+		}
+	}
+	return result
+}